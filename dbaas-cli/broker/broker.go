@@ -0,0 +1,435 @@
+// Package broker implements the Open Service Broker API v2 over the dbaas library.
+// The service instances are the database clusters created with the preset of the plan.
+// The instance ID is recorded in the cluster object annotation, so the broker keeps no state
+// of its own and the instances survive its restarts. The bindings share the cluster credentials
+package broker
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Annotations of the clusters created by the broker
+const (
+	InstanceAnnotation = dbaas.AnnotationPrefix + "broker-instance"
+	PlanAnnotation     = dbaas.AnnotationPrefix + "broker-plan"
+)
+
+// APIVersionHeader is the header the platform sends the version of the API in
+const APIVersionHeader = "X-Broker-API-Version"
+
+// Last operation states
+const (
+	StateInProgress = "in progress"
+	StateSucceeded  = "succeeded"
+	StateFailed     = "failed"
+)
+
+// Config is the configuration of the broker
+type Config struct {
+	// Provider is the provider the databases are created with, "k8s" by default
+	Provider string
+	// Username and Password are the basic auth credentials of the platform. The auth is off if Username is empty
+	Username string
+	Password string
+}
+
+// Broker is the Open Service Broker
+type Broker struct {
+	conf Config
+	// engines keep the state of the cluster they work with, so the calls are serialized
+	engines sync.Mutex
+}
+
+// New returns the broker with the given configuration
+func New(conf Config) *Broker {
+	if len(conf.Provider) == 0 {
+		conf.Provider = "k8s"
+	}
+
+	return &Broker{conf: conf}
+}
+
+// ProvisionRequest is the body of PUT /v2/service_instances/{instance_id}
+type ProvisionRequest struct {
+	ServiceID  string     `json:"service_id"`
+	PlanID     string     `json:"plan_id"`
+	Parameters Parameters `json:"parameters,omitempty"`
+}
+
+// Parameters are the parameters of the provision request
+type Parameters struct {
+	// Name is the name of the cluster, it is derived from the instance ID by default
+	Name string `json:"name,omitempty"`
+	// Options are the engine options in the "spec.pxc.size=3,spec.proxysql.enabled=false" format
+	Options string            `json:"options,omitempty"`
+	Version string            `json:"version,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// BindRequest is the body of PUT /v2/service_instances/{instance_id}/service_bindings/{binding_id}
+type BindRequest struct {
+	ServiceID string `json:"service_id"`
+	PlanID    string `json:"plan_id"`
+}
+
+// Credentials are the credentials of the binding
+type Credentials struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	URI      string `json:"uri"`
+}
+
+// Error is the body of the failed responses
+type Error struct {
+	Error       string `json:"error,omitempty"`
+	Description string `json:"description"`
+}
+
+// Handler returns the handler of the broker routes
+func (b *Broker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/catalog", b.handleCatalog)
+	mux.HandleFunc("/v2/service_instances/", b.handleInstances)
+
+	return b.authenticate(mux)
+}
+
+// authenticate checks the basic auth credentials and the API version of the requests
+func (b *Broker) authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(b.conf.Username) > 0 {
+			user, pass, ok := r.BasicAuth()
+			if !ok || subtle.ConstantTimeCompare([]byte(user), []byte(b.conf.Username)) != 1 ||
+				subtle.ConstantTimeCompare([]byte(pass), []byte(b.conf.Password)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="percona-dbaas"`)
+				writeJSON(w, http.StatusUnauthorized, Error{Description: "unauthorized"})
+				return
+			}
+		}
+		if v := r.Header.Get(APIVersionHeader); !strings.HasPrefix(v, "2.") {
+			writeJSON(w, http.StatusPreconditionFailed, Error{Description: "the " + APIVersionHeader + " header should be 2.x, got " + strconv.Quote(v)})
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (b *Broker) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, catalog(b.conf.Provider))
+}
+
+// handleInstances routes /v2/service_instances/{instance_id}[/last_operation|/service_bindings/{binding_id}]
+func (b *Broker) handleInstances(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/service_instances/"), "/")
+	if len(parts[0]) == 0 {
+		writeJSON(w, http.StatusNotFound, Error{Description: "instance ID is required"})
+		return
+	}
+	id := parts[0]
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		b.provision(w, r, id)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		b.deprovision(w, r, id)
+	case len(parts) == 1:
+		methodNotAllowed(w, http.MethodPut, http.MethodDelete)
+	case len(parts) == 2 && parts[1] == "last_operation" && r.Method == http.MethodGet:
+		b.lastOperation(w, r, id)
+	case len(parts) == 2 && parts[1] == "last_operation":
+		methodNotAllowed(w, http.MethodGet)
+	case len(parts) == 3 && parts[1] == "service_bindings" && len(parts[2]) > 0 && r.Method == http.MethodPut:
+		b.bind(w, r, id)
+	case len(parts) == 3 && parts[1] == "service_bindings" && len(parts[2]) > 0 && r.Method == http.MethodDelete:
+		b.unbind(w, r, id)
+	case len(parts) == 3 && parts[1] == "service_bindings" && len(parts[2]) > 0:
+		methodNotAllowed(w, http.MethodPut, http.MethodDelete)
+	default:
+		writeJSON(w, http.StatusNotFound, Error{Description: "unknown path " + r.URL.Path})
+	}
+}
+
+func (b *Broker) provision(w http.ResponseWriter, r *http.Request, id string) {
+	// creating the cluster takes minutes, so the provisioning is always asynchronous
+	if r.URL.Query().Get("accepts_incomplete") != "true" {
+		writeJSON(w, http.StatusUnprocessableEntity, Error{Error: "AsyncRequired", Description: "the databases are provisioned asynchronously"})
+		return
+	}
+	var req ProvisionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{Description: "decode request: " + err.Error()})
+		return
+	}
+	engine, preset, ok := plan(b.conf.Provider, req.ServiceID, req.PlanID)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, Error{Description: "unknown plan " + req.PlanID + " of service " + req.ServiceID})
+		return
+	}
+
+	db, found, err := b.find(id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Description: err.Error()})
+		return
+	}
+	if found {
+		// the platform retries the request, the same one is fine
+		if db.Engine != engine || db.Annotations[PlanAnnotation] != req.PlanID {
+			writeJSON(w, http.StatusConflict, Error{Description: "instance " + id + " already exists with another plan"})
+			return
+		}
+		if db.Status == dbaas.StateReady {
+			writeJSON(w, http.StatusOK, struct{}{})
+			return
+		}
+		writeJSON(w, http.StatusAccepted, operation{Operation: "provision"})
+		return
+	}
+
+	name := req.Parameters.Name
+	if len(name) == 0 {
+		name = clusterName(id)
+	}
+	for k, v := range req.Parameters.Labels {
+		if err := dbaas.ValidateLabel(k, v); err != nil {
+			writeJSON(w, http.StatusBadRequest, Error{Description: err.Error()})
+			return
+		}
+	}
+	instance := dbaas.Instance{
+		Provider:      b.conf.Provider,
+		Engine:        engine,
+		Name:          name,
+		Preset:        preset,
+		EngineOptions: req.Parameters.Options,
+		Version:       req.Parameters.Version,
+		Labels:        req.Parameters.Labels,
+		Annotations: map[string]string{
+			InstanceAnnotation: id,
+			PlanAnnotation:     req.PlanID,
+		},
+	}
+	// the cluster object is created right away, its status is polled with last_operation
+	b.engines.Lock()
+	err = dbaas.CreateDB(instance)
+	b.engines.Unlock()
+	if errors.Is(err, dbaas.ErrAlreadyExists) {
+		// the cluster of the name belongs to another instance or isn't made by the broker
+		writeJSON(w, http.StatusConflict, Error{Description: "create database: " + err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{Description: "create database: " + err.Error()})
+		return
+	}
+	log.Infof("instance %s: provisioning %s/%s with plan %s", id, engine, name, preset)
+
+	writeJSON(w, http.StatusAccepted, operation{Operation: "provision"})
+}
+
+func (b *Broker) deprovision(w http.ResponseWriter, r *http.Request, id string) {
+	db, found, err := b.find(id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Description: err.Error()})
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusGone, struct{}{})
+		return
+	}
+
+	b.engines.Lock()
+	_, err = dbaas.DeleteDB(dbaas.Instance{Provider: b.conf.Provider, Engine: db.Engine, Name: db.ResourceName}, true)
+	b.engines.Unlock()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Description: "delete database: " + err.Error()})
+		return
+	}
+	log.Infof("instance %s: deprovisioned %s/%s", id, db.Engine, db.ResourceName)
+
+	// the cluster object is deleted synchronously, so there is nothing to poll
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (b *Broker) lastOperation(w http.ResponseWriter, r *http.Request, id string) {
+	db, found, err := b.find(id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Description: err.Error()})
+		return
+	}
+	if !found {
+		if r.URL.Query().Get("operation") == "deprovision" {
+			writeJSON(w, http.StatusGone, struct{}{})
+			return
+		}
+		writeJSON(w, http.StatusOK, lastOperation{State: StateFailed, Description: "instance " + id + " not found"})
+		return
+	}
+
+	switch db.Status {
+	case dbaas.StateReady:
+		writeJSON(w, http.StatusOK, lastOperation{State: StateSucceeded, Description: "database is ready"})
+	case dbaas.StateError:
+		writeJSON(w, http.StatusOK, lastOperation{State: StateFailed, Description: "database is in error state"})
+	default:
+		writeJSON(w, http.StatusOK, lastOperation{State: StateInProgress, Description: "database is " + string(db.Status)})
+	}
+}
+
+func (b *Broker) bind(w http.ResponseWriter, r *http.Request, id string) {
+	var req BindRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{Description: "decode request: " + err.Error()})
+		return
+	}
+	db, found, err := b.find(id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Description: err.Error()})
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusBadRequest, Error{Description: "instance " + id + " not found"})
+		return
+	}
+	if db.Status != dbaas.StateReady {
+		writeJSON(w, http.StatusUnprocessableEntity, Error{Error: "ConcurrencyError", Description: "database is " + string(db.Status)})
+		return
+	}
+
+	// the list doesn't read the secrets, the cluster is described to get the credentials
+	b.engines.Lock()
+	described, err := dbaas.DescribeDB(dbaas.Instance{Provider: b.conf.Provider, Engine: db.Engine, Name: db.ResourceName})
+	b.engines.Unlock()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Description: "describe database: " + err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, binding{Credentials: credentials(db.Engine, described)})
+}
+
+func (b *Broker) unbind(w http.ResponseWriter, r *http.Request, id string) {
+	_, found, err := b.find(id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Description: err.Error()})
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusGone, struct{}{})
+		return
+	}
+
+	// the bindings share the cluster credentials, so there is nothing to revoke
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+// find returns the database of the instance. It fails if several databases have the instance
+// annotation, e.g. the one copied by hand, as deprovisioning could delete the wrong one
+func (b *Broker) find(id string) (dbaas.DB, bool, error) {
+	b.engines.Lock()
+	list, errs, err := dbaas.ListAllDB(dbaas.ListFilter{})
+	b.engines.Unlock()
+	if err != nil {
+		return dbaas.DB{}, false, errors.Wrap(err, "list databases")
+	}
+	var found []dbaas.DB
+	for _, db := range list {
+		if db.Provider == b.conf.Provider && db.Annotations[InstanceAnnotation] == id {
+			found = append(found, db)
+		}
+	}
+	switch len(found) {
+	case 0:
+	case 1:
+		return found[0], true, nil
+	default:
+		names := make([]string, 0, len(found))
+		for _, db := range found {
+			names = append(names, db.Engine+"/"+db.ResourceName)
+		}
+		return dbaas.DB{}, false, errors.Errorf("instance %s is found in several databases: %s", id, strings.Join(names, ", "))
+	}
+	// the instance can be in the engine which failed to list its databases
+	if len(errs) > 0 {
+		return dbaas.DB{}, false, errors.Wrap(errs[0], "list databases")
+	}
+
+	return dbaas.DB{}, false, nil
+}
+
+type operation struct {
+	Operation string `json:"operation"`
+}
+
+type lastOperation struct {
+	State       string `json:"state"`
+	Description string `json:"description,omitempty"`
+}
+
+type binding struct {
+	Credentials Credentials `json:"credentials"`
+}
+
+var nameChars = regexp.MustCompile("[^a-z0-9]")
+
+// clusterName derives the cluster name from the instance ID. The IDs are usually GUIDs,
+// so the first characters of them are unique enough and keep the name short
+func clusterName(id string) string {
+	name := nameChars.ReplaceAllString(strings.ToLower(id), "")
+	if len(name) > 12 {
+		name = name[:12]
+	}
+
+	return "osb-" + name
+}
+
+func credentials(engine string, db dbaas.DB) Credentials {
+	u := url.URL{
+		Scheme: scheme(engine),
+		User:   url.UserPassword(db.User, db.Pass),
+		Host:   net.JoinHostPort(db.ResourceEndpoint, strconv.Itoa(db.Port)),
+		Path:   "/",
+	}
+
+	return Credentials{
+		Host:     db.ResourceEndpoint,
+		Port:     db.Port,
+		Username: db.User,
+		Password: db.Pass,
+		URI:      u.String(),
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, Error{Description: "method not allowed"})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Error("write response: ", err)
+	}
+}
@@ -0,0 +1,115 @@
+package broker
+
+import (
+	"strings"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Service is the service offering of the catalog
+type Service struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Bindable       bool     `json:"bindable"`
+	PlanUpdateable bool     `json:"plan_updateable"`
+	Tags           []string `json:"tags,omitempty"`
+	Plans          []Plan   `json:"plans"`
+}
+
+// Plan is the plan of the service, there is a plan for each sizing preset
+type Plan struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Free        bool   `json:"free"`
+	Bindable    bool   `json:"bindable"`
+}
+
+// Catalog is the response of GET /v2/catalog
+type Catalog struct {
+	Services []Service `json:"services"`
+}
+
+// services are the engines offered by the broker
+var services = []struct {
+	engine, name, description string
+	tags                      []string
+	scheme                    string
+}{
+	{engine: "pxc", name: "percona-mysql", description: "Percona XtraDB Cluster, the highly available MySQL cluster", tags: []string{"mysql", "pxc"}, scheme: "mysql"},
+	{engine: "psmdb", name: "percona-mongodb", description: "Percona Server for MongoDB replica set", tags: []string{"mongodb", "psmdb"}, scheme: "mongodb"},
+}
+
+// serviceID is stable, so the platforms keep the instances between the broker restarts
+func serviceID(engine string) string {
+	return "percona-dbaas-" + engine
+}
+
+func planID(engine, preset string) string {
+	return serviceID(engine) + "-" + preset
+}
+
+// catalog lists the services of the engines registered for the provider with the plans of the presets
+func catalog(provider string) Catalog {
+	c := Catalog{Services: []Service{}}
+	for _, s := range services {
+		if _, ok := dbaas.Providers[provider].Engines[s.engine]; !ok {
+			continue
+		}
+		svc := Service{
+			ID:          serviceID(s.engine),
+			Name:        s.name,
+			Description: s.description,
+			Bindable:    true,
+			Tags:        s.tags,
+		}
+		for _, name := range dbaas.PresetNames() {
+			preset, err := dbaas.GetPreset(name)
+			if err != nil {
+				continue
+			}
+			desc := preset.Description
+			if len(desc) == 0 {
+				desc = "The " + name + " preset"
+			}
+			svc.Plans = append(svc.Plans, Plan{
+				ID:          planID(s.engine, name),
+				Name:        name,
+				Description: desc,
+				Free:        true,
+				Bindable:    true,
+			})
+		}
+		c.Services = append(c.Services, svc)
+	}
+
+	return c
+}
+
+// plan returns the engine and the preset of the plan of the service
+func plan(provider, service, plan string) (engine, preset string, ok bool) {
+	for _, s := range catalog(provider).Services {
+		if s.ID != service {
+			continue
+		}
+		for _, p := range s.Plans {
+			if p.ID == plan {
+				return strings.TrimPrefix(s.ID, serviceID("")), p.Name, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// scheme returns the URI scheme of the engine credentials
+func scheme(engine string) string {
+	for _, s := range services {
+		if s.engine == engine {
+			return s.scheme
+		}
+	}
+
+	return engine
+}
@@ -1,7 +1,6 @@
 package client
 
 import (
-	"errors"
 	"strings"
 	"time"
 
@@ -19,6 +18,8 @@ func GetInstance(name, options, engine, provider, rootPass string) dbaas.Instanc
 	}
 }
 
+// GetDB waits for the database to become ready. Unless hidePass is set the password
+// is put into the connection details instead of the PASSWORD placeholder
 func GetDB(instance dbaas.Instance, hidePass, noWait bool, maxTries int) (dbaas.DB, error) {
 	cluster := dbaas.DB{}
 	tries := 0
@@ -26,31 +27,36 @@ func GetDB(instance dbaas.Instance, hidePass, noWait bool, maxTries int) (dbaas.
 	defer tckr.Stop()
 	for range tckr.C {
 		cluster, err := dbaas.DescribeDB(instance)
-		if err != nil && err != k8s.ErrOutOfMemory {
-			//log.Error("check db: ", err)
-			continue
-		}
-		if hidePass {
-			cluster.Pass = "PASSWORD"
-		}
-		switch cluster.Status {
-		case dbaas.StateReady:
-			cluster.Message = strings.Replace(cluster.Message, "PASSWORD", cluster.Pass, 1)
-			return cluster, nil
-		case dbaas.StateInit:
-			if noWait {
+		// the cluster may be not visible yet, it is checked again until the time is out
+		if err == nil || k8s.IsClusterProblem(err) {
+			if hidePass {
+				cluster.Pass = ""
+			} else {
 				cluster.Message = strings.Replace(cluster.Message, "PASSWORD", cluster.Pass, 1)
+			}
+			switch cluster.Status {
+			case dbaas.StateReady:
 				return cluster, nil
+			case dbaas.StateInit:
+				if noWait {
+					return cluster, nil
+				}
+			case dbaas.StateError:
+				return cluster, err
 			}
-		case dbaas.StateError:
-			return cluster, err
 		}
 
 		if tries >= maxTries {
-			return cluster, errors.New("cluster status: " + string(cluster.Status))
+			return cluster, ErrTimeout{Status: string(cluster.Status)}
 		}
 		tries++
 	}
 
-	return cluster, errors.New("cluster status: " + string(cluster.Status))
+	return cluster, ErrTimeout{Status: string(cluster.Status)}
+}
+
+// ProblemHint tells what can be done about the error preventing the cluster from starting,
+// it is empty if the error isn't such a problem
+func ProblemHint(err error) string {
+	return k8s.ProblemHint(err)
 }
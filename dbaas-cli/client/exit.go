@@ -0,0 +1,73 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Exit codes of the commands. Scripts can rely on them, the codes are never reused
+const (
+	ExitOK                    = 0
+	ExitError                 = 1
+	ExitValidation            = 2
+	ExitNotFound              = 3
+	ExitAlreadyExists         = 4
+	ExitTimeout               = 5
+	ExitInsufficientResources = 6
+	ExitPermissionDenied      = 7
+)
+
+// ValidationError is the error in the arguments or the flags of the command
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Validation marks the error as the validation error, nil stays nil
+func Validation(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return ValidationError{Err: err}
+}
+
+// ErrTimeout is returned when the cluster doesn't get ready in time
+type ErrTimeout struct {
+	Status string
+}
+
+func (e ErrTimeout) Error() string {
+	if len(e.Status) == 0 {
+		return "timeout waiting for the cluster"
+	}
+
+	return "timeout waiting for the cluster, the last status is " + e.Status
+}
+
+// ExitCode returns the exit code for the error type
+func ExitCode(err error) int {
+	var invalid dbaas.ErrInvalidOption
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &ValidationError{}), errors.As(err, &invalid), errors.Is(err, dbaas.ErrUnsupportedVersion), errors.Is(err, dbaas.ErrDataLoss):
+		return ExitValidation
+	case errors.Is(err, dbaas.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, dbaas.ErrAlreadyExists):
+		return ExitAlreadyExists
+	case errors.As(err, &ErrTimeout{}):
+		return ExitTimeout
+	case errors.Is(err, dbaas.ErrInsufficientResources):
+		return ExitInsufficientResources
+	case errors.Is(err, dbaas.ErrPermissionDenied):
+		return ExitPermissionDenied
+	}
+
+	return ExitError
+}
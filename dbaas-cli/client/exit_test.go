@@ -0,0 +1,35 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+func TestExitCode(t *testing.T) {
+	for _, c := range []struct {
+		err  error
+		code int
+	}{
+		{nil, ExitOK},
+		{errors.New("connection refused"), ExitError},
+		{Validation(errors.New("unknown output format")), ExitValidation},
+		{errors.Wrap(k8s.ErrNotFound, "get cluster object"), ExitNotFound},
+		{errors.Wrap(k8s.ErrAlreadyExists{Typ: "pxc", Cluster: "orders"}, "create cluster"), ExitAlreadyExists},
+		{ErrTimeout{Status: "initializing"}, ExitTimeout},
+		{errors.Wrapf(k8s.ErrInsufficientResources, "nodes can fit only %d of %d new pods", 1, 3), ExitInsufficientResources},
+		{k8s.ErrClusterProblem{Kind: k8s.ProblemScheduling, Object: "pod/orders-pxc-0"}, ExitInsufficientResources},
+		{k8s.ErrClusterProblem{Kind: k8s.ProblemImagePull, Object: "pod/orders-pxc-0"}, ExitError},
+		{errors.Wrap(k8s.ErrOutOfMemory, "wait for cluster"), ExitInsufficientResources},
+		{errors.Wrap(dbaas.ErrInvalidOption{Path: "spec.pxc.size"}, "parse options"), ExitValidation},
+		{errors.Wrapf(dbaas.ErrUnsupportedVersion, "operator version %s", "0.9.0"), ExitValidation},
+		{errors.Wrap(dbaas.ErrPermissionDenied, "get pods"), ExitPermissionDenied},
+	} {
+		if code := ExitCode(c.err); code != c.code {
+			t.Errorf("%v: got exit code %d, want %d", c.err, code, c.code)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"strings"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// ParseLabelArgs splits the "key=value" labels to set from the "key-" ones to remove
+func ParseLabelArgs(args []string) (map[string]string, []string, error) {
+	set := make(map[string]string)
+	var remove []string
+	for _, arg := range args {
+		if !strings.Contains(arg, "=") && strings.HasSuffix(arg, "-") {
+			key := strings.TrimSuffix(arg, "-")
+			err := dbaas.ValidateLabel(key, "")
+			if err != nil {
+				return nil, nil, err
+			}
+			remove = append(remove, key)
+			continue
+		}
+		labels, err := dbaas.ParseLabels(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range labels {
+			set[k] = v
+		}
+	}
+
+	return set, remove, nil
+}
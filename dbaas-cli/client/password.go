@@ -0,0 +1,45 @@
+package client
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GetPassword returns the password given either as the flag value, in the file or on the standard input.
+// Only one source can be used at once
+func GetPassword(pass, passFile string, passStdin bool) (string, error) {
+	sources := 0
+	for _, set := range []bool{len(pass) > 0, len(passFile) > 0, passStdin} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return "", errors.New("only one of --password, --password-file and --password-stdin can be used")
+	}
+
+	switch {
+	case len(passFile) > 0:
+		b, err := ioutil.ReadFile(passFile)
+		if err != nil {
+			return "", errors.Wrap(err, "read password file")
+		}
+		pass = strings.TrimRight(string(b), "\r\n")
+	case passStdin:
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil && len(line) == 0 {
+			return "", errors.Wrap(err, "read password from stdin")
+		}
+		pass = strings.TrimRight(line, "\r\n")
+	}
+	if (len(passFile) > 0 || passStdin) && len(pass) == 0 {
+		return "", errors.New("empty password")
+	}
+
+	return pass, nil
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// PresetsFile returns the path of the user presets config file
+func PresetsFile() string {
+	return os.Getenv("HOME") + "/.percona/presets.yaml"
+}
+
+// LoadPresets registers the user presets in addition to the built-in ones if the config file exists
+func LoadPresets() error {
+	f := PresetsFile()
+	if _, err := os.Stat(f); os.IsNotExist(err) {
+		return nil
+	}
+
+	return errors.WithMessage(dbaas.LoadPresets(f), "load presets")
+}
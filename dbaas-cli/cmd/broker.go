@@ -0,0 +1,103 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/broker"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+)
+
+// brokerCmd represents the broker command
+var brokerCmd = &cobra.Command{
+	Use:   "broker",
+	Short: "Run the Open Service Broker",
+	Long: `Runs the Open Service Broker API v2 server, so the platforms like Cloud Foundry or the Kubernetes
+Service Catalog provision the MySQL and MongoDB clusters. The plans are the sizing presets.
+
+The platform authenticates with the basic auth credentials given with --username and --password.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		verbosity, err := cmd.Flags().GetCount("verbose")
+		if err != nil {
+			exit("get verbose flag: ", err)
+		}
+		op.Configure(op.FormatText, false, verbosity)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		err := client.LoadPresets()
+		if err != nil {
+			exit(err)
+		}
+		pass, err := client.GetPassword(*brokerPassword, *brokerPasswordFile, false)
+		if err != nil {
+			exit(client.Validation(err))
+		}
+		if len(*brokerUsername) == 0 || len(pass) == 0 {
+			exit(client.Validation(errors.New("--username and --password or --password-file are required")))
+		}
+
+		b := broker.New(broker.Config{
+			Username: *brokerUsername,
+			Password: pass,
+		})
+		srv := &http.Server{
+			Addr:    *brokerAddress,
+			Handler: b.Handler(),
+		}
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-stop
+			log.Println("Shutting down")
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Error("shutdown: ", err)
+			}
+		}()
+
+		log.Printf("Serving the service broker on http://%s/v2", *brokerAddress)
+		err = srv.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			exit("serve: ", err)
+		}
+	},
+}
+
+var brokerAddress *string
+var brokerUsername *string
+var brokerPassword *string
+var brokerPasswordFile *string
+
+func init() {
+	brokerAddress = brokerCmd.Flags().String("address", "127.0.0.1:8081", "Address to listen on")
+	brokerUsername = brokerCmd.Flags().String("username", "", "Basic auth username of the platform")
+	brokerPassword = brokerCmd.Flags().String("password", "", "Basic auth password of the platform")
+	brokerPasswordFile = brokerCmd.Flags().String("password-file", "", "File with the basic auth password of the platform")
+
+	rootCmd.AddCommand(brokerCmd)
+}
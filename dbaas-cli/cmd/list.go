@@ -0,0 +1,108 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List databases of all engines",
+	Long:  "Lists the database clusters of all the providers and engines, e.g. MySQL and MongoDB clusters together.",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exit("get output flag: ", err)
+		}
+		showSecrets, err = cmd.Flags().GetBool("show-secrets")
+		if err != nil {
+			exit("get show-secrets flag: ", err)
+		}
+		verbosity, err := cmd.Flags().GetCount("verbose")
+		if err != nil {
+			exit("get verbose flag: ", err)
+		}
+		op.Configure(output, showSecrets, verbosity)
+		if err := op.ValidateFormat(output); err != nil {
+			exit(client.Validation(err))
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := dbaas.ListFilter{
+			Labels: *listLabels,
+			Status: dbaas.State(*listStatus),
+		}
+		listDB, errs, err := dbaas.ListAllDB(filter)
+		if err != nil {
+			exit("list db: ", err)
+		}
+		// an engine which isn't installed or is unreachable doesn't hide the databases of the others
+		for _, err := range errs {
+			log.Warn(err)
+		}
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exit("get output flag: ", err)
+		}
+		switch {
+		case op.IsStructured(format):
+			// scripts get the empty list rather than the message
+			if listDB == nil {
+				listDB = []dbaas.DB{}
+			}
+			err = op.PrintResult(os.Stdout, format, op.KindDatabaseList, listDB, showSecrets)
+			if err != nil {
+				exit("print db list: ", err)
+			}
+		case len(listDB) == 0:
+			log.Println("Nothing to show")
+		default:
+			w := new(tabwriter.Writer)
+			w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+			fmt.Fprintln(w, "NAME\tPROVIDER\tENGINE\tVERSION\tREADY\tAGE\tSTATUS\t")
+			for _, db := range listDB {
+				status := string(db.Status)
+				if db.Paused {
+					status += " (paused)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", db.ResourceName, db.Provider, db.Engine, db.EngineVersion, db.Ready(), db.Age(), status)
+			}
+			fmt.Fprintln(w)
+			w.Flush()
+		}
+	},
+}
+
+var listLabels *string
+var listStatus *string
+var showSecrets bool
+
+func init() {
+	listLabels = listCmd.Flags().StringP("selector", "l", "", `Label selector, e.g. "team=payments,env!=dev"`)
+	listStatus = listCmd.Flags().String("status", "", `Show only the databases in the status, e.g. "ready", "initializing" or "error"`)
+
+	rootCmd.AddCommand(listCmd)
+}
@@ -15,11 +15,12 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/cmd/mongo"
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/cmd/mysql"
 )
@@ -33,15 +34,30 @@ var rootCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringP("output", "o", "text", `Answers format. Can be "json" or "text".`)
+	rootCmd.PersistentFlags().StringP("output", "o", "text", `Answers format. Can be "text", "json", "yaml", "jsonpath=<template>" or "go-template=<template>". The templates are applied to the results of describe-db and list, other commands write JSON with them`)
 	rootCmd.AddCommand(mysql.PXCCmd)
 	rootCmd.AddCommand(mongo.MongoCmd)
 	rootCmd.PersistentFlags().Bool("no-wait", false, "Dont wait while command is done")
+	rootCmd.PersistentFlags().Bool("show-secrets", false, "Show passwords and other secret values in the output")
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Show the executed kubectl commands and their timings, -vv adds the output of the failed ones")
+}
+
+// exit logs the error like log.Error and exits with the code of the error type
+func exit(args ...interface{}) {
+	log.Error(args...)
+	code := client.ExitError
+	for _, a := range args {
+		if err, ok := a.(error); ok {
+			code = client.ExitCode(err)
+		}
+	}
+	os.Exit(code)
 }
 
 func main() {
+	// the commands exit on their own errors, so Execute fails only on the wrong arguments
+	// or flags. Cobra has already written the error to stderr
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(client.ExitValidation)
 	}
 }
@@ -0,0 +1,95 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
+)
+
+// cloneCmd represents the clone-db command
+var cloneCmd = &cobra.Command{
+	Use:   "clone-db <source-mongo-cluster-name> <target-mongo-cluster-name>",
+	Short: "Clone MongoDB cluster",
+	Long: `Creates a new cluster of the same version and with the same options as the source one and copies the data into it.
+
+The data volumes are copied with volume snapshots if the CSI driver of their storage class supports them.
+Otherwise the on-demand backup of the source cluster is taken to its backup storage and restored into the new cluster, the backup is kept.
+The options given with --options override the ones of the source cluster, e.g. --options="replsets.size=3".
+The labels of the source cluster aren't copied, the ones of the new cluster are given with --labels.
+The backup schedules of the source cluster aren't copied either, so the clone doesn't write its backups
+into the storages of the source, unless --keep-backup-schedules is given.
+The command waits until the new cluster is ready and has the data.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("You have to specify source and target resource names")
+		}
+		if args[0] == args[1] {
+			return errors.New("source and target names should differ")
+		}
+		if _, err := dbaas.ParseLabels(*cloneLabels); err != nil {
+			return err
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[1], addSpec(*cloneOptions), *cloneEngine, *cloneProvider, "")
+		instance.KeepBackupSchedules = *cloneKeepSchedules
+		labels, err := dbaas.ParseLabels(*cloneLabels)
+		if err != nil {
+			exit(err)
+		}
+		instance.Labels = labels
+
+		dotPrinter.Start("Cloning")
+		err = dbaas.CloneDB(args[0], instance, time.Duration(maxTries)*500*time.Millisecond)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("clone db: ", err)
+		}
+		cluster, err := client.GetDB(instance, !showSecrets, true, maxTries)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("get cluster: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.WithField("database", cluster).Info("Database cloned successfully, connection details are below:")
+	},
+}
+
+var cloneOptions *string
+var cloneProvider *string
+var cloneEngine *string
+var cloneLabels *string
+var cloneKeepSchedules *bool
+
+func init() {
+	cloneOptions = cloneCmd.Flags().String("options", "", "Engine options overriding the ones of the source cluster in 'p1.p2=text' format")
+	cloneProvider = cloneCmd.Flags().String("provider", "k8s", "Provider")
+	cloneEngine = cloneCmd.Flags().String("engine", "psmdb", "Engine")
+	cloneLabels = cloneCmd.Flags().String("labels", "", "Labels of the new cluster and its pods in 'key=value' format")
+	cloneKeepSchedules = cloneCmd.Flags().Bool("keep-backup-schedules", false, "Keep the backup schedules of the source cluster")
+
+	MongoCmd.AddCommand(cloneCmd)
+}
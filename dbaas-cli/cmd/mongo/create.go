@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
 	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
 )
@@ -37,33 +38,71 @@ var createCmd = &cobra.Command{
 		if len(args) == 0 {
 			return errors.New("You have to specify resource name")
 		}
+		switch *tlsMode {
+		case dbaas.TLSAuto, dbaas.TLSOff:
+			if len(*tlsSecret) > 0 || len(*tlsInternalSecret) > 0 {
+				return errors.New("--tls-secret and --tls-internal-secret can be used only with --tls=custom")
+			}
+		case dbaas.TLSCustom:
+			if len(*tlsSecret) == 0 {
+				return errors.New("--tls-secret is required with --tls=custom")
+			}
+		default:
+			return errors.Errorf(`unknown TLS mode "%s"`, *tlsMode)
+		}
+		if len(*fromPreserved) > 0 && (len(*rootPass) > 0 || len(*rootPassFile) > 0 || *rootPassStdin) {
+			return errors.New("the password can't be set with --from-preserved, the users of the deleted cluster are kept")
+		}
+		if _, err := dbaas.ParseLabels(*createLabels); err != nil {
+			return err
+		}
 
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		instance := client.GetInstance(args[0], addSpec(*options), *engine, *provider, *rootPass)
+		pass, err := client.GetPassword(*rootPass, *rootPassFile, *rootPassStdin)
+		if err != nil {
+			exit(err)
+		}
+		err = client.LoadPresets()
+		if err != nil {
+			exit(err)
+		}
+		op.AddSecret(pass)
+		instance := client.GetInstance(args[0], addSpec(*options), *engine, *provider, pass)
+		instance.UsersSecret = *usersSecret
+		instance.Preset = *preset
+		instance.TLS = *tlsMode
+		instance.TLSSecret = *tlsSecret
+		instance.TLSInternalSecret = *tlsInternalSecret
+		instance.FromPreserved = *fromPreserved
+		instance.Labels, err = dbaas.ParseLabels(*createLabels)
+		if err != nil {
+			exit(err)
+		}
+		instance.Encryption = *encryption
 
 		warns, err := dbaas.PreCheck(instance)
 		for _, w := range warns {
-			log.Println("Warning:", w)
+			log.Warn("Warning: ", w)
 		}
 		if err != nil {
-			log.Error(err)
-			return
+			exit(err)
 		}
 
 		dotPrinter.Start("Starting")
 		err = dbaas.CreateDB(instance)
 		if err != nil {
 			dotPrinter.Stop("error")
-			log.Error("create db: ", err)
-			return
+			exit("create db: ", err)
 		}
-		cluster, err := client.GetDB(instance, false, noWait, maxTries)
+		cluster, err := client.GetDB(instance, !showSecrets, noWait, maxTries)
 		if err != nil {
 			dotPrinter.Stop("error")
-			log.Errorf("unable to start cluster: %v", err)
-			return
+			if hint := client.ProblemHint(err); len(hint) > 0 {
+				log.Errorf("%s, run 'describe-db %s --events' for details", hint, args[0])
+			}
+			exit("unable to start cluster: ", err)
 		}
 
 		if cluster.Status == dbaas.StateInit {
@@ -81,12 +120,32 @@ var options *string
 var provider *string
 var engine *string
 var rootPass *string
+var rootPassFile *string
+var rootPassStdin *bool
+var usersSecret *string
+var preset *string
+var tlsMode *string
+var tlsSecret *string
+var tlsInternalSecret *string
+var fromPreserved *string
+var createLabels *string
+var encryption *bool
 
 func init() {
 	options = createCmd.Flags().String("options", "", "Engine options in 'p1.p2=text' format. For k8s/psmdb use params from https://www.percona.com/doc/kubernetes-operator-for-psmongodb/operator.html")
 	provider = createCmd.Flags().String("provider", "k8s", "Provider")
 	engine = createCmd.Flags().String("engine", "psmdb", "Engine")
-	rootPass = createCmd.Flags().String("password", "", "Password for superuser")
+	rootPass = createCmd.Flags().String("password", "", "Password for superuser. Use --password-file or --password-stdin to keep it out of the shell history")
+	rootPassFile = createCmd.Flags().String("password-file", "", "Read the superuser password from the file")
+	rootPassStdin = createCmd.Flags().Bool("password-stdin", false, "Read the superuser password from the standard input")
+	usersSecret = createCmd.Flags().String("users-secret", "", "Use the existing secret with users credentials instead of creating a new one")
+	preset = createCmd.Flags().String("preset", "", "Sizing preset: small, medium, large or the one defined in ~/.percona/presets.yaml. Options given with --options override it")
+	tlsMode = createCmd.Flags().String("tls", "off", `TLS for client and intra-cluster connections: "auto" issues certificates with cert-manager or the self-signed CA, "custom" uses the certificates from --tls-secret, "off" leaves TLS unconfigured. Requires operator 1.3.0 or newer`)
+	tlsSecret = createCmd.Flags().String("tls-secret", "", "Secret with ca.crt, tls.crt and tls.key for client connections in custom TLS mode")
+	tlsInternalSecret = createCmd.Flags().String("tls-internal-secret", "", "Secret with the certificates for intra-cluster traffic in custom TLS mode. Defaults to --tls-secret")
+	fromPreserved = createCmd.Flags().String("from-preserved", "", "Create the cluster with the data kept by 'delete-db --preserve-data' of the cluster with this name. See list-orphaned-data")
+	createLabels = createCmd.Flags().String("labels", "", "Labels of the cluster and its pods in 'key=value' format, e.g. 'team=payments,env=prod'")
+	encryption = createCmd.Flags().Bool("encryption", false, "Enable data-at-rest encryption with the key generated for the cluster. Requires operator 1.3.0 or newer")
 
 	MongoCmd.AddCommand(createCmd)
 }
@@ -73,11 +73,10 @@ var delCmd = &cobra.Command{
 
 		warns, err := dbaas.PreCheck(instance)
 		for _, w := range warns {
-			log.Println("Warning:", w)
+			log.Warn("Warning: ", w)
 		}
 		if err != nil {
-			log.Error(err)
-			return
+			exit(err)
 		}
 
 		dotPrinter.Start("Deleting")
@@ -85,13 +84,13 @@ var delCmd = &cobra.Command{
 		dataStorage, err := dbaas.DeleteDB(instance, deletePVC)
 		if err != nil {
 			dotPrinter.Stop("error")
-			log.Error("delete db: ", err)
-			return
+			exit("delete db: ", err)
 		}
 
 		dotPrinter.Stop("done")
 		if *preserve {
 			log.Println("Your data is stored in " + dataStorage)
+			log.Printf("Use 'create-db --from-preserved %s' to create the cluster with it or 'purge-data %s' to delete it", args[0], args[0])
 		}
 	},
 }
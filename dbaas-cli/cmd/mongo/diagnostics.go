@@ -0,0 +1,86 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
+)
+
+// diagnosticsCmd represents the collect-diagnostics command
+var diagnosticsCmd = &cobra.Command{
+	Use:   "collect-diagnostics <mongo-cluster-name>",
+	Short: "Collect diagnostics of MongoDB cluster for support",
+	Long: `Gathers the cluster custom resource, the operator deployment and logs, pod and volume claim descriptions,
+events and CRD versions into the tar.gz archive with manifest.json listing the collected files.
+
+Secrets values aren't collected, only the names and the keys of the cluster secrets.
+The archive is written to <cluster>-diagnostics-<time>.tar.gz in the current directory unless --file is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *diagnosticsEngine, *diagnosticsProvider, "")
+		now := time.Now()
+		file := *diagnosticsFile
+		if len(file) == 0 {
+			file = dbaas.DiagnosticsDir(args[0], now) + ".tar.gz"
+		}
+		f, err := os.Create(file)
+		if err != nil {
+			exit("create archive: ", err)
+		}
+
+		dotPrinter.Start("Collecting")
+		err = dbaas.CollectDiagnostics(instance, now, f)
+		if err == nil {
+			err = f.Close()
+		} else {
+			f.Close()
+		}
+		if err != nil {
+			dotPrinter.Stop("error")
+			os.Remove(file)
+			exit("collect diagnostics: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.Println("Diagnostics are written to " + file)
+	},
+}
+
+var diagnosticsProvider *string
+var diagnosticsEngine *string
+var diagnosticsFile *string
+
+func init() {
+	diagnosticsFile = diagnosticsCmd.Flags().StringP("file", "f", "", "Archive file to write, e.g. bundle.tar.gz")
+	diagnosticsProvider = diagnosticsCmd.Flags().String("provider", "k8s", "Provider")
+	diagnosticsEngine = diagnosticsCmd.Flags().String("engine", "psmdb", "Engine")
+
+	MongoCmd.AddCommand(diagnosticsCmd)
+}
@@ -0,0 +1,97 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
+)
+
+// healthCmd represents the health command
+var healthCmd = &cobra.Command{
+	Use:   "health <mongo-cluster-name>",
+	Short: "Check health of MongoDB cluster",
+	Long: `Checks the cluster status reported by the operator, the phase, readiness and restarts of the cluster pods,
+the binding of the volume claims and the pod disruption budgets.
+
+The command exits with non-zero code if any of the checks failed or the checks couldn't be done, so it can be used in CI and cron jobs.
+Warnings, like container restarts, don't change the exit code.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *healthEngine, *healthProvider, "")
+		health, err := dbaas.CheckHealth(instance)
+		if err != nil {
+			exit("check health: ", err)
+		}
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exit("get output flag: ", err)
+		}
+		switch {
+		case op.IsStructured(format):
+			log.WithField("health", health).Info("information")
+		default:
+			w := new(tabwriter.Writer)
+			w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+			fmt.Fprintln(w, "COMPONENT\tOBJECT\tSTATUS\tMESSAGE\t")
+			for _, c := range health.Checks {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", c.Component, c.Object, c.Status, c.Message)
+			}
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "Cluster %s is %s\n", health.Cluster, healthVerdict(health.Status))
+			w.Flush()
+		}
+		if health.Status == dbaas.HealthFailed {
+			os.Exit(1)
+		}
+	},
+}
+
+func healthVerdict(status string) string {
+	switch status {
+	case dbaas.HealthOK:
+		return "healthy"
+	case dbaas.HealthWarning:
+		return "healthy with warnings"
+	}
+
+	return "unhealthy"
+}
+
+var healthProvider *string
+var healthEngine *string
+
+func init() {
+	healthProvider = healthCmd.Flags().String("provider", "k8s", "Provider")
+	healthEngine = healthCmd.Flags().String("engine", "psmdb", "Engine")
+
+	MongoCmd.AddCommand(healthCmd)
+}
@@ -0,0 +1,73 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs <mongo-cluster-name>",
+	Short: "Show logs of MongoDB cluster",
+	Long: `Shows the logs of the cluster pods, each line prefixed with the pod and the container names,
+and the lines of the operator logs mentioning the cluster.
+
+Use --component to show the logs of mongod or operator only.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *logsEngine, *logsProvider, "")
+		logs := dbaas.Logs{
+			Component: *logsComponent,
+			Follow:    *logsFollow,
+			Since:     *logsSince,
+		}
+
+		err := dbaas.StreamLogs(instance, logs, os.Stdout)
+		if err != nil {
+			exit("logs: ", err)
+		}
+	},
+}
+
+var logsProvider *string
+var logsEngine *string
+var logsComponent *string
+var logsFollow *bool
+var logsSince *time.Duration
+
+func init() {
+	logsComponent = logsCmd.Flags().String("component", "", "Component to show the logs of: mongod or operator")
+	logsFollow = logsCmd.Flags().BoolP("follow", "f", false, "Keep streaming the new lines")
+	logsSince = logsCmd.Flags().Duration("since", 0, "Show the lines newer than the duration, e.g. 5s, 2m or 3h")
+	logsProvider = logsCmd.Flags().String("provider", "k8s", "Provider")
+	logsEngine = logsCmd.Flags().String("engine", "psmdb", "Engine")
+
+	MongoCmd.AddCommand(logsCmd)
+}
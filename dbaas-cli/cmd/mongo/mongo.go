@@ -15,8 +15,10 @@
 package mongo
 
 import (
+	"os"
 	"strings"
 
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
 	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/pb"
 	"github.com/pkg/errors"
@@ -25,9 +27,10 @@ import (
 )
 
 var (
-	dotPrinter pb.ProgressBar
-	noWait     bool
-	maxTries   = 1200
+	dotPrinter  pb.ProgressBar
+	noWait      bool
+	showSecrets bool
+	maxTries    = 1200
 )
 
 // MongoCmd represents the mysql command
@@ -37,20 +40,41 @@ var MongoCmd = &cobra.Command{
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		output, err := cmd.Flags().GetString("output")
 		if err != nil {
-			log.Error(errors.Wrap(err, "get output flag value"))
-			return
+			exit(errors.Wrap(err, "get output flag value"))
+		}
+		showSecrets, err = cmd.Flags().GetBool("show-secrets")
+		if err != nil {
+			exit(errors.Wrap(err, "get show-secrets flag"))
+		}
+		verbosity, err := cmd.Flags().GetCount("verbose")
+		if err != nil {
+			exit(errors.Wrap(err, "get verbose flag"))
 		}
 		dotPrinter = op.GetDotprinter(output)
-		log.SetFormatter(op.GetFormatter(output))
+		op.Configure(output, showSecrets, verbosity)
+		if err := op.ValidateFormat(output); err != nil {
+			exit(client.Validation(err))
+		}
 
 		noWait, err = cmd.Flags().GetBool("no-wait")
 		if err != nil {
-			log.Error(errors.Wrap(err, "get no-wait flag"))
-			return
+			exit(errors.Wrap(err, "get no-wait flag"))
 		}
 	},
 }
 
+// exit logs the error like log.Error and exits with the code of the error type
+func exit(args ...interface{}) {
+	log.Error(args...)
+	code := client.ExitError
+	for _, a := range args {
+		if err, ok := a.(error); ok {
+			code = client.ExitCode(err)
+		}
+	}
+	os.Exit(code)
+}
+
 func addSpec(opts string) string {
 	if len(opts) == 0 {
 		return ""
@@ -0,0 +1,93 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
+)
+
+// listPreservedCmd represents the list-orphaned-data command
+var listPreservedCmd = &cobra.Command{
+	Use:   "list-orphaned-data",
+	Short: "List data of deleted MongoDB clusters",
+	Long: `Lists the data volumes kept by 'delete-db --preserve-data' grouped by the deleted cluster.
+
+Use 'create-db --from-preserved <old-name>' to create a cluster with the data or 'purge-data <old-name>' to delete it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance("", "", *listPreservedEngine, *listPreservedProvider, "")
+		list, err := dbaas.ListPreservedData(instance)
+		if err != nil {
+			exit("list orphaned data: ", err)
+		}
+		if len(list) == 0 {
+			log.Println("Nothing to show")
+			return
+		}
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exit("get output flag: ", err)
+		}
+		switch {
+		case op.IsStructured(format):
+			log.WithField("orphaned-data", list).Info("information")
+		default:
+			w := new(tabwriter.Writer)
+			w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+			fmt.Fprintln(w, "CLUSTER\tENGINE\tVERSION\tVOLUMES\tSIZE\tAGE\tPRESERVED\t")
+			for _, d := range list {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t\n", d.Cluster, d.Engine, valueOrUnknown(d.Version), len(d.Volumes), d.Size, since(d.Created), since(d.PreservedAt))
+			}
+			w.Flush()
+		}
+	},
+}
+
+func valueOrUnknown(s string) string {
+	if len(s) == 0 {
+		return "<unknown>"
+	}
+
+	return s
+}
+
+func since(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+
+	return duration.HumanDuration(time.Since(t)) + " ago"
+}
+
+var listPreservedProvider *string
+var listPreservedEngine *string
+
+func init() {
+	listPreservedProvider = listPreservedCmd.Flags().String("provider", "k8s", "Provider")
+	listPreservedEngine = listPreservedCmd.Flags().String("engine", "psmdb", "Engine")
+
+	MongoCmd.AddCommand(listPreservedCmd)
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
+)
+
+// resizeCmd represents the resize-storage command
+var resizeCmd = &cobra.Command{
+	Use:   "resize-storage <mongo-cluster-name>",
+	Short: "Expand storage of MongoDB cluster",
+	Long: `Expands the data volumes of the existing cluster online.
+
+The storage class of the volumes has to allow volume expansion. Volumes can't be shrunk.
+The new size is also set for the cluster, so the members added later get the volumes of the same size.
+If the storage driver resizes file systems offline, the command tells which pods have to be restarted.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("you have to specify resource name")
+		}
+		if len(*resizeSize) == 0 {
+			return errors.New("--size is required")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *resizeEngine, *resizeProvider, "")
+		var wait time.Duration
+		if !noWait {
+			wait = time.Duration(maxTries) * 500 * time.Millisecond
+		}
+
+		dotPrinter.Start("Resizing")
+		msg, err := dbaas.ResizeStorage(instance, *resizeSize, wait)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("resize storage: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		if len(msg) > 0 {
+			log.Warn(msg)
+		}
+		log.Println("Storage resized successfully")
+	},
+}
+
+var resizeProvider *string
+var resizeEngine *string
+var resizeSize *string
+
+func init() {
+	resizeSize = resizeCmd.Flags().String("size", "", "New size of the data volumes, e.g. 50G")
+	resizeProvider = resizeCmd.Flags().String("provider", "k8s", "Provider")
+	resizeEngine = resizeCmd.Flags().String("engine", "psmdb", "Engine")
+
+	MongoCmd.AddCommand(resizeCmd)
+}
@@ -0,0 +1,97 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// rotateKeyCmd represents the rotate-encryption-key command
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-encryption-key <mongo-cluster-name>",
+	Short: "Rotate data-at-rest encryption key of MongoDB cluster",
+	Long: `Replaces the data-at-rest encryption key of MongoDB cluster created with --encryption.
+
+MongoDB reads the key only at startup and can't re-encrypt existing data files, so every
+replica set member is restarted with an empty data directory, one at a time, and does the
+initial sync from the other members. The cluster stays available while the members are
+resynced, but it runs with reduced redundancy and the primary steps down when its turn
+comes. The rotation takes as long as the initial sync of all members, so it depends on
+the data size. Every replica set needs at least two members.
+
+The previous key and the list of the resynced members are kept in the
+"<encryption-key-secret>-previous" secret until all members are resynced. If the rotation
+is interrupted, run the command again: it keeps both keys and resyncs only the members
+that aren't resynced yet.
+
+A member that isn't resynced yet can't start if it restarts for any other reason during
+the rotation, as it reads the new key while its data is encrypted with the previous one.
+Resume the rotation to resync it.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("you have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *rotateKeyEngine, *rotateKeyProvider, "")
+
+		if !*rotateKeyForced {
+			var yn string
+			fmt.Printf("ARE YOU SURE YOU WANT TO ROTATE THE ENCRYPTION KEY OF THE DATABASE '%s'? Yes/No\nALL MEMBERS WILL BE RESYNCED ONE BY ONE\n", args[0])
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				yn = strings.TrimSpace(scanner.Text())
+				break
+			}
+			if yn != "yes" && yn != "Yes" && yn != "YES" && yn != "Y" && yn != "y" {
+				return
+			}
+		}
+
+		dotPrinter.Start("Rotating")
+		err := dbaas.RotateEncryptionKey(instance)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("rotate encryption key: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.Println("Encryption key rotated successfully")
+	},
+}
+
+var rotateKeyProvider *string
+var rotateKeyEngine *string
+var rotateKeyForced *bool
+
+func init() {
+	rotateKeyForced = rotateKeyCmd.Flags().BoolP("yes", "y", false, "Unswer yes for questions")
+	rotateKeyProvider = rotateKeyCmd.Flags().String("provider", "k8s", "Provider")
+	rotateKeyEngine = rotateKeyCmd.Flags().String("engine", "psmdb", "Engine")
+
+	MongoCmd.AddCommand(rotateKeyCmd)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb"
+)
+
+// scaleCmd represents the scale command
+var scaleCmd = &cobra.Command{
+	Use:   "scale-db <mongo-cluster-name>",
+	Short: "Scale MongoDB cluster",
+	Long: `Changes the number of the members of the replset given with --replset or of all replsets of the existing cluster.
+
+An even number of nodes can't keep the quorum in case of the network split, so it is rejected unless --force is given.
+The command checks that the kubernetes nodes have enough resources for the new pods of each replset and waits until they are ready.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("you have to specify resource name")
+		}
+		if *scaleReplicas < 1 {
+			return errors.New("--replicas should be positive")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *scaleEngine, *scaleProvider, "")
+		scale := dbaas.Scale{
+			Replicas: *scaleReplicas,
+			Replset:  *scaleReplset,
+			Force:    *scaleForce,
+		}
+		if !noWait {
+			scale.Wait = time.Duration(maxTries) * 500 * time.Millisecond
+		}
+
+		dotPrinter.Start("Scaling")
+		err := dbaas.ScaleDB(instance, scale)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("scale db: ", err)
+		}
+
+		cluster, err := client.GetDB(instance, true, noWait, maxTries)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("get db: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.WithField("database", cluster).Info("Database scaled successfully, connection details are below:")
+	},
+}
+
+var scaleProvider *string
+var scaleEngine *string
+var scaleReplicas *int32
+var scaleReplset *string
+var scaleForce *bool
+
+func init() {
+	scaleReplicas = scaleCmd.Flags().Int32("replicas", 0, "Number of the replset members")
+	scaleReplset = scaleCmd.Flags().String("replset", "", "Name of the replset to scale, all replsets are scaled by default")
+	scaleForce = scaleCmd.Flags().Bool("force", false, "Allow an even number of the replset members")
+	scaleProvider = scaleCmd.Flags().String("provider", "k8s", "Provider")
+	scaleEngine = scaleCmd.Flags().String("engine", "psmdb", "Engine")
+
+	MongoCmd.AddCommand(scaleCmd)
+}
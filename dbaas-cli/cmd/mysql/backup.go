@@ -0,0 +1,211 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-pxc"
+)
+
+// backupScheduleCmd represents the backup-schedule command
+var backupScheduleCmd = &cobra.Command{
+	Use:   "backup-schedule",
+	Short: "Manage scheduled backups of MySQL cluster",
+}
+
+var backupScheduleAddCmd = &cobra.Command{
+	Use:   "add <mysql-cluster-name>",
+	Short: "Add scheduled backup",
+	Long: `Adds the backup the operator takes periodically by the cron schedule in the standard 5-field format, e.g. "0 2 * * *".
+
+The storage is added to the cluster if its S3 bucket or the volume size is given, otherwise the cluster should have it already.
+S3 credentials are taken from --s3-credentials-secret or the secret is created from the access keys.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+		if len(*bsName) == 0 || len(*bsSchedule) == 0 || len(*bsStorage) == 0 {
+			return errors.New("--name, --schedule and --storage are required")
+		}
+		if len(*bsS3Bucket) > 0 && len(*bsPVCSize) > 0 {
+			return errors.New("--s3-bucket and --pvc-size can't be used together")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *bsEngine, *bsProvider, "")
+		schedule := dbaas.BackupSchedule{
+			Name:     *bsName,
+			Schedule: *bsSchedule,
+			Storage:  *bsStorage,
+			Keep:     *bsKeep,
+		}
+		var storage *dbaas.BackupStorage
+		switch {
+		case len(*bsS3Bucket) > 0:
+			op.AddSecret(*bsS3Key)
+			storage = &dbaas.BackupStorage{
+				Type:              "s3",
+				Bucket:            *bsS3Bucket,
+				Region:            *bsS3Region,
+				EndpointURL:       *bsS3Endpoint,
+				CredentialsSecret: *bsS3Secret,
+				KeyID:             *bsS3KeyID,
+				Key:               *bsS3Key,
+			}
+		case len(*bsPVCSize) > 0:
+			storage = &dbaas.BackupStorage{
+				Type:         "filesystem",
+				Size:         *bsPVCSize,
+				StorageClass: *bsPVCStorageClass,
+			}
+		}
+
+		err := dbaas.AddBackupSchedule(instance, schedule, storage)
+		if err != nil {
+			exit("add backup schedule: ", err)
+		}
+
+		log.Println("Backup schedule added successfully")
+	},
+}
+
+var backupScheduleListCmd = &cobra.Command{
+	Use:   "list <mysql-cluster-name>",
+	Short: "List scheduled backups and backup storages",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *bsEngine, *bsProvider, "")
+		schedules, storages, err := dbaas.ListBackupSchedules(instance)
+		if err != nil {
+			exit("list backup schedules: ", err)
+		}
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exit("get output flag: ", err)
+		}
+		switch {
+		case op.IsStructured(format):
+			log.WithField("backup-schedules", schedules).WithField("backup-storages", storages).Info("information")
+		default:
+			w := new(tabwriter.Writer)
+			w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+			fmt.Fprintln(w, "SCHEDULE\tCRON\tSTORAGE\tKEEP\t")
+			for _, s := range schedules {
+				keep := "all"
+				if s.Keep > 0 {
+					keep = fmt.Sprint(s.Keep)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", s.Name, s.Schedule, s.Storage, keep)
+			}
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "STORAGE\tTYPE\tLOCATION\t")
+			for _, s := range storages {
+				fmt.Fprintf(w, "%s\t%s\t%s\t\n", s.Name, s.Type, storageLocation(s))
+			}
+			w.Flush()
+		}
+	},
+}
+
+func storageLocation(s dbaas.BackupStorage) string {
+	switch s.Type {
+	case "s3":
+		loc := "s3://" + s.Bucket
+		if len(s.EndpointURL) > 0 {
+			loc = s.EndpointURL + "/" + s.Bucket
+		}
+		return loc
+	case "filesystem":
+		return "pvc " + s.Size
+	}
+
+	return ""
+}
+
+var backupScheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <mysql-cluster-name> <schedule-name>",
+	Short: "Remove scheduled backup",
+	Long:  "Removes the scheduled backup. The storage and the backups already taken are kept.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("You have to specify resource and schedule names")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *bsEngine, *bsProvider, "")
+		err := dbaas.RemoveBackupSchedule(instance, args[1])
+		if err != nil {
+			exit("remove backup schedule: ", err)
+		}
+
+		log.Println("Backup schedule removed successfully")
+	},
+}
+
+var bsProvider *string
+var bsEngine *string
+var bsName *string
+var bsSchedule *string
+var bsStorage *string
+var bsKeep *int
+var bsS3Bucket *string
+var bsS3Region *string
+var bsS3Endpoint *string
+var bsS3Secret *string
+var bsS3KeyID *string
+var bsS3Key *string
+var bsPVCSize *string
+var bsPVCStorageClass *string
+
+func init() {
+	bsProvider = backupScheduleCmd.PersistentFlags().String("provider", "k8s", "Provider")
+	bsEngine = backupScheduleCmd.PersistentFlags().String("engine", "pxc", "Engine")
+
+	bsName = backupScheduleAddCmd.Flags().String("name", "", "Name of the schedule")
+	bsSchedule = backupScheduleAddCmd.Flags().String("schedule", "", `Cron schedule, e.g. "0 2 * * *"`)
+	bsStorage = backupScheduleAddCmd.Flags().String("storage", "", "Name of the backup storage")
+	bsKeep = backupScheduleAddCmd.Flags().Int("keep", 0, "Number of backups to keep, all by default")
+	bsS3Bucket = backupScheduleAddCmd.Flags().String("s3-bucket", "", "Add S3 storage with this bucket")
+	bsS3Region = backupScheduleAddCmd.Flags().String("s3-region", "", "S3 region")
+	bsS3Endpoint = backupScheduleAddCmd.Flags().String("s3-endpoint-url", "", "Endpoint URL of S3 compatible storage")
+	bsS3Secret = backupScheduleAddCmd.Flags().String("s3-credentials-secret", "", "Secret with AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	bsS3KeyID = backupScheduleAddCmd.Flags().String("s3-access-key-id", "", "S3 access key id")
+	bsS3Key = backupScheduleAddCmd.Flags().String("s3-secret-access-key", "", "S3 secret access key")
+	bsPVCSize = backupScheduleAddCmd.Flags().String("pvc-size", "", "Add filesystem storage on the volume of this size, e.g. 10G")
+	bsPVCStorageClass = backupScheduleAddCmd.Flags().String("pvc-storage-class", "", "Storage class of the filesystem storage volume")
+
+	backupScheduleCmd.AddCommand(backupScheduleAddCmd, backupScheduleListCmd, backupScheduleRemoveCmd)
+	PXCCmd.AddCommand(backupScheduleCmd)
+}
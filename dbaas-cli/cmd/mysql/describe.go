@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
 	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 )
 
@@ -39,40 +40,79 @@ var describeCmd = &cobra.Command{
 		instance := client.GetInstance(name, "", *descrEngine, *descrProvider, "")
 
 		if len(name) > 0 {
-			db, err := dbaas.DescribeDB(instance)
+			db, describeErr := dbaas.DescribeDB(instance)
+			if describeErr != nil {
+				log.Error("describe db: ", describeErr)
+				if hint := client.ProblemHint(describeErr); len(hint) > 0 {
+					log.Error(hint)
+				}
+				// the events tell why the cluster isn't ready
+				if !*descrEvents {
+					os.Exit(client.ExitCode(describeErr))
+				}
+			}
+			var err error
+			if *descrEvents {
+				db.Events, err = dbaas.ListEvents(instance)
+				if err != nil {
+					exit("list events: ", err)
+				}
+			}
+			if !showSecrets {
+				db.Pass = ""
+			}
+			format, err := cmd.Flags().GetString("output")
 			if err != nil {
-				log.Error("describe db: ", err)
-				return
+				exit("get output flag: ", err)
+			}
+			if op.IsStructured(format) {
+				err = op.PrintResult(os.Stdout, format, op.KindDatabase, db, showSecrets)
+				if err != nil {
+					exit("print db: ", err)
+				}
+			} else {
+				log.WithField("database", db).Info("information")
+			}
+			if describeErr != nil {
+				os.Exit(client.ExitCode(describeErr))
 			}
-			db.Pass = ""
-			log.WithField("database", db).Info("information")
 			return
 		}
 
 		listDB, err := dbaas.ListDB(instance)
 		if err != nil {
-			log.Error("list db: ", err)
-			return
+			exit("list db: ", err)
 		}
-		if len(listDB) == 0 {
-			log.Println("Nothing to show")
-			return
+		listDB, err = dbaas.FilterDB(listDB, dbaas.ListFilter{Labels: *descrSelector})
+		if err != nil {
+			exit("filter db: ", err)
 		}
 		format, err := cmd.Flags().GetString("output")
 		if err != nil {
-			log.Error("get output flag: ", err)
-			return
+			exit("get output flag: ", err)
 		}
-		switch format {
-		case "json":
-			log.WithField("database-list", listDB).Info("information")
+		switch {
+		case op.IsStructured(format):
+			// scripts get the empty list rather than the message
+			if listDB == nil {
+				listDB = []dbaas.DB{}
+			}
+			err = op.PrintResult(os.Stdout, format, op.KindDatabaseList, listDB, showSecrets)
+			if err != nil {
+				exit("print db list: ", err)
+			}
+		case len(listDB) == 0:
+			log.Println("Nothing to show")
 		default:
 			w := new(tabwriter.Writer)
 			w.Init(os.Stdout, 0, 8, 0, '\t', 0)
-			fmt.Fprintln(w, "NAME\tSTATUS\t")
+			fmt.Fprintln(w, "NAME\tENGINE\tVERSION\tREADY\tAGE\tSTATUS\t")
 			for _, db := range listDB {
-				fmt.Fprintln(w, fmt.Sprintf("%s\t%s", db.ResourceName, db.Status))
-
+				status := string(db.Status)
+				if db.Paused {
+					status += " (paused)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t\n", db.ResourceName, db.Engine, db.EngineVersion, db.Ready(), db.Age(), status)
 			}
 			fmt.Fprintln(w)
 			w.Flush()
@@ -82,9 +122,13 @@ var describeCmd = &cobra.Command{
 
 var descrProvider *string
 var descrEngine *string
+var descrEvents *bool
+var descrSelector *string
 
 func init() {
 	descrProvider = describeCmd.Flags().String("provider", "k8s", "Provider")
+	descrEvents = describeCmd.Flags().Bool("events", false, "Show the timeline of the events of the cluster objects")
+	descrSelector = describeCmd.Flags().StringP("selector", "l", "", `Label selector of the listed clusters, e.g. "team=payments,env!=dev"`)
 	descrEngine = describeCmd.Flags().String("engine", "pxc", "Engine")
 
 	PXCCmd.AddCommand(describeCmd)
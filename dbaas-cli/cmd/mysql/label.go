@@ -0,0 +1,78 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-pxc"
+)
+
+// labelCmd represents the label command
+var labelCmd = &cobra.Command{
+	Use:   "label-db <mysql-cluster-name> <key=value>... [key-]...",
+	Short: "Change labels of MySQL cluster",
+	Long: `Sets the labels given as "key=value" and removes the ones given as "key-" from the cluster and its pods.
+
+The operator restarts the pods to apply the labels. Use 'describe-db --selector' and 'list --selector' to find the clusters by labels.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+		if len(args) == 1 {
+			return errors.New("You have to specify labels to set or remove")
+		}
+		_, _, err := client.ParseLabelArgs(args[1:])
+
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		set, remove, err := client.ParseLabelArgs(args[1:])
+		if err != nil {
+			exit(err)
+		}
+		instance := client.GetInstance(args[0], "", *labelEngine, *labelProvider, "")
+
+		dotPrinter.Start("Labeling")
+		err = dbaas.LabelDB(instance, set, remove)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("label db: ", err)
+		}
+
+		cluster, err := client.GetDB(instance, true, noWait, maxTries)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("get db: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.WithField("database", cluster).Info("Database labeled successfully, connection details are below:")
+	},
+}
+
+var labelProvider *string
+var labelEngine *string
+
+func init() {
+	labelProvider = labelCmd.Flags().String("provider", "k8s", "Provider")
+	labelEngine = labelCmd.Flags().String("engine", "pxc", "Engine")
+
+	PXCCmd.AddCommand(labelCmd)
+}
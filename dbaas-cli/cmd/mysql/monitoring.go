@@ -0,0 +1,118 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-pxc"
+)
+
+// enableMonitoringCmd represents the enable-monitoring command
+var enableMonitoringCmd = &cobra.Command{
+	Use:   "enable-monitoring <mysql-cluster-name>",
+	Short: "Enable PMM monitoring of MySQL cluster",
+	Long: `Adds the PMM client sidecars to the cluster pods, so the cluster is monitored by the PMM server.
+
+The PMM server password is read from the "password" key of the secret given with --pmm-password-secret.
+The server host, user and password can be omitted if they were set before.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *enableMonitoringEngine, *enableMonitoringProvider, "")
+		m := dbaas.Monitoring{
+			ServerHost:     *pmmServer,
+			ServerUser:     *pmmUser,
+			PasswordSecret: *pmmPasswordSecret,
+		}
+		if !noWait {
+			m.Wait = time.Duration(maxTries) * 500 * time.Millisecond
+		}
+
+		dotPrinter.Start("Enabling monitoring")
+		err := dbaas.EnableMonitoring(instance, m)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("enable monitoring: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.Println("Monitoring enabled successfully")
+	},
+}
+
+// disableMonitoringCmd represents the disable-monitoring command
+var disableMonitoringCmd = &cobra.Command{
+	Use:   "disable-monitoring <mysql-cluster-name>",
+	Short: "Disable PMM monitoring of MySQL cluster",
+	Long:  "Removes the PMM client sidecars from the cluster pods. The PMM server credentials are kept in the cluster secrets.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *disableMonitoringEngine, *disableMonitoringProvider, "")
+		var wait time.Duration
+		if !noWait {
+			wait = time.Duration(maxTries) * 500 * time.Millisecond
+		}
+
+		dotPrinter.Start("Disabling monitoring")
+		err := dbaas.DisableMonitoring(instance, wait)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("disable monitoring: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.Println("Monitoring disabled successfully")
+	},
+}
+
+var enableMonitoringProvider *string
+var enableMonitoringEngine *string
+var disableMonitoringProvider *string
+var disableMonitoringEngine *string
+var pmmServer *string
+var pmmUser *string
+var pmmPasswordSecret *string
+
+func init() {
+	pmmServer = enableMonitoringCmd.Flags().String("pmm-server", "", "PMM server host")
+	pmmUser = enableMonitoringCmd.Flags().String("pmm-user", "", "PMM server user")
+	pmmPasswordSecret = enableMonitoringCmd.Flags().String("pmm-password-secret", "", "Secret with the PMM server password under the \"password\" key")
+	enableMonitoringProvider = enableMonitoringCmd.Flags().String("provider", "k8s", "Provider")
+	enableMonitoringEngine = enableMonitoringCmd.Flags().String("engine", "pxc", "Engine")
+
+	disableMonitoringProvider = disableMonitoringCmd.Flags().String("provider", "k8s", "Provider")
+	disableMonitoringEngine = disableMonitoringCmd.Flags().String("engine", "pxc", "Engine")
+
+	PXCCmd.AddCommand(enableMonitoringCmd)
+	PXCCmd.AddCommand(disableMonitoringCmd)
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-pxc"
+)
+
+// purgeCmd represents the purge-data command
+var purgeCmd = &cobra.Command{
+	Use:   "purge-data <old-mysql-cluster-name>",
+	Short: "Delete data of deleted MySQL cluster",
+	Long:  "Deletes the data volumes and the users secret kept by 'delete-db --preserve-data'.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("you have to specify resource name")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *purgeEngine, *purgeProvider, "")
+
+		if !*purgeForced {
+			var yn string
+			fmt.Printf("ARE YOU SURE YOU WANT TO DELETE THE PRESERVED DATA OF '%s'? Yes/No\nALL THE DATA WILL BE LOST.\n", args[0])
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				yn = strings.TrimSpace(scanner.Text())
+				break
+			}
+			if yn != "yes" && yn != "Yes" && yn != "YES" && yn != "Y" && yn != "y" {
+				return
+			}
+		}
+
+		dotPrinter.Start("Deleting")
+		err := dbaas.PurgePreservedData(instance)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("purge data: ", err)
+		}
+
+		dotPrinter.Stop("done")
+	},
+}
+
+var purgeProvider *string
+var purgeEngine *string
+var purgeForced *bool
+
+func init() {
+	purgeForced = purgeCmd.Flags().BoolP("yes", "y", false, "Answer yes for questions")
+	purgeProvider = purgeCmd.Flags().String("provider", "k8s", "Provider")
+	purgeEngine = purgeCmd.Flags().String("engine", "pxc", "Engine")
+
+	PXCCmd.AddCommand(purgeCmd)
+}
@@ -0,0 +1,151 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-pxc"
+)
+
+const restoreTimeLayout = "2006-01-02 15:04:05"
+
+// restoreCmd represents the restore-db command
+var restoreCmd = &cobra.Command{
+	Use:   "restore-db <mysql-cluster-name>",
+	Short: "Restore MySQL cluster from backup",
+	Long: `Restores the cluster data from the backup given with --backup or as of the time given with --to-time.
+
+With --to-time the latest successful backup completed before the time is selected and shown before the
+confirmation. Point-in-time recovery isn't supported: it needs binlogs collected since the backup, which the
+supported operator versions don't do. So if the backup completed before the given time, the changes made
+after it would be lost and the command fails unless --accept-data-loss is given.
+The time is in UTC unless --to-time has a zone offset.
+The current data of the cluster is replaced.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+		if (len(*restoreBackup) == 0) == (len(*restoreToTime) == 0) {
+			return errors.New("either --backup or --to-time is required")
+		}
+		if *restoreAcceptDataLoss && len(*restoreToTime) == 0 {
+			return errors.New("--accept-data-loss is used with --to-time only")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *restoreEngine, *restoreProvider, "")
+		restore := dbaas.Restore{
+			Backup: *restoreBackup,
+		}
+		if len(*restoreToTime) > 0 {
+			t, err := parseRestoreTime(*restoreToTime)
+			if err != nil {
+				exit(err)
+			}
+			restore.ToTime = t
+			restore.AcceptDataLoss = *restoreAcceptDataLoss
+		}
+		if !noWait {
+			restore.Wait = time.Duration(maxTries) * 500 * time.Millisecond
+		}
+
+		lost := false
+		if !restore.ToTime.IsZero() {
+			b, err := dbaas.SelectBackup(instance, restore.ToTime)
+			if err != nil {
+				exit("select backup: ", err)
+			}
+			log.Printf("Backup %s completed at %s is selected", b.Name, b.Completed.UTC().Format(restoreTimeLayout))
+			lost = restore.ToTime.After(b.Completed)
+			if lost && !restore.AcceptDataLoss {
+				exit(client.Validation(errors.Errorf("the changes made after %s until %s would be lost as point-in-time recovery isn't supported, use --accept-data-loss to restore the backup anyway",
+					b.Completed.UTC().Format(restoreTimeLayout), restore.ToTime.UTC().Format(restoreTimeLayout))))
+			}
+			// restore the backup the user has seen even if the new one completes in the meantime
+			restore.Backup = b.Name
+		}
+
+		if !*restoreForced {
+			var yn string
+			fmt.Printf("ARE YOU SURE YOU WANT TO RESTORE THE DATABASE '%s'? Yes/No\nTHE CURRENT DATA WILL BE REPLACED.\n", args[0])
+			if lost {
+				fmt.Printf("THE DATA IS RESTORED FROM THE BACKUP %s, THE CHANGES MADE AFTER IT ARE LOST.\n", restore.Backup)
+			}
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				yn = strings.TrimSpace(scanner.Text())
+				break
+			}
+			if yn != "yes" && yn != "Yes" && yn != "YES" && yn != "Y" && yn != "y" {
+				return
+			}
+		}
+
+		dotPrinter.Start("Restoring")
+		msg, err := dbaas.RestoreDB(instance, restore)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("restore db: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		if len(msg) > 0 {
+			log.Warn(msg)
+		}
+		log.Println("Restore started successfully")
+	},
+}
+
+func parseRestoreTime(s string) (time.Time, error) {
+	for _, layout := range []string{restoreTimeLayout + "Z07:00", restoreTimeLayout, time.RFC3339} {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.Errorf(`invalid time %q, use "%s" format`, s, restoreTimeLayout)
+}
+
+var restoreProvider *string
+var restoreEngine *string
+var restoreBackup *string
+var restoreToTime *string
+var restoreForced *bool
+var restoreAcceptDataLoss *bool
+
+func init() {
+	restoreForced = restoreCmd.Flags().BoolP("yes", "y", false, "Answer yes for questions")
+	restoreBackup = restoreCmd.Flags().String("backup", "", "Name of the backup to restore")
+	restoreToTime = restoreCmd.Flags().String("to-time", "", `Time to restore the data to, e.g. "2026-10-01 12:00:00"`)
+	restoreAcceptDataLoss = restoreCmd.Flags().Bool("accept-data-loss", false, "Restore the backup completed before --to-time, losing the changes made after it")
+	restoreProvider = restoreCmd.Flags().String("provider", "k8s", "Provider")
+	restoreEngine = restoreCmd.Flags().String("engine", "pxc", "Engine")
+
+	PXCCmd.AddCommand(restoreCmd)
+}
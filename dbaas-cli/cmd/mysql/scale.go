@@ -0,0 +1,90 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	_ "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-pxc"
+)
+
+// scaleCmd represents the scale command
+var scaleCmd = &cobra.Command{
+	Use:   "scale-db <mysql-cluster-name>",
+	Short: "Scale MySQL cluster",
+	Long: `Changes the number of PXC nodes and ProxySQL pods of the existing cluster.
+
+An even number of nodes can't keep the quorum in case of the network split, so it is rejected unless --force is given.
+The command checks that the kubernetes nodes have enough resources for the new pods and waits until they are ready.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You have to specify resource name")
+		}
+		if *scaleReplicas < 1 {
+			return errors.New("--replicas should be positive")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		instance := client.GetInstance(args[0], "", *scaleEngine, *scaleProvider, "")
+		scale := dbaas.Scale{
+			Replicas: *scaleReplicas,
+			Proxies:  *scaleProxies,
+			Force:    *scaleForce,
+		}
+		if !noWait {
+			scale.Wait = time.Duration(maxTries) * 500 * time.Millisecond
+		}
+
+		dotPrinter.Start("Scaling")
+		err := dbaas.ScaleDB(instance, scale)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("scale db: ", err)
+		}
+
+		cluster, err := client.GetDB(instance, true, noWait, maxTries)
+		if err != nil {
+			dotPrinter.Stop("error")
+			exit("get db: ", err)
+		}
+
+		dotPrinter.Stop("done")
+		log.WithField("database", cluster).Info("Database scaled successfully, connection details are below:")
+	},
+}
+
+var scaleProvider *string
+var scaleEngine *string
+var scaleReplicas *int32
+var scaleProxies *int32
+var scaleForce *bool
+
+func init() {
+	scaleReplicas = scaleCmd.Flags().Int32("replicas", 0, "Number of PXC nodes")
+	scaleProxies = scaleCmd.Flags().Int32("proxy", 0, "Number of ProxySQL pods. The current number is kept if it is not set")
+	scaleForce = scaleCmd.Flags().Bool("force", false, "Allow an even number of PXC nodes")
+	scaleProvider = scaleCmd.Flags().String("provider", "k8s", "Provider")
+	scaleEngine = scaleCmd.Flags().String("engine", "pxc", "Engine")
+
+	PXCCmd.AddCommand(scaleCmd)
+}
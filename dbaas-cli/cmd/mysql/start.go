@@ -43,26 +43,23 @@ var startCmd = &cobra.Command{
 
 		warns, err := dbaas.PreCheck(instance)
 		for _, w := range warns {
-			log.Println("Warning:", w)
+			log.Warn("Warning: ", w)
 		}
 		if err != nil {
-			log.Error(err)
-			return
+			exit(err)
 		}
 		dotPrinter.Start("Starting")
 		err = dbaas.ModifyDB(instance)
 		if err != nil {
 			dotPrinter.Stop("error")
-			log.Error("modify db: ", err)
-			return
+			exit("modify db: ", err)
 		}
 		time.Sleep(time.Second * 10) //let k8s time for applying new cr
 
 		cluster, err := client.GetDB(instance, true, noWait, maxTries)
 		if err != nil {
 			dotPrinter.Stop("error")
-			log.Errorf("unable to start cluster:", err)
-			return
+			exit("unable to start cluster: ", err)
 		}
 
 		if cluster.Status == dbaas.StateInit {
@@ -0,0 +1,105 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/server"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the HTTP API",
+	Long: `Runs the HTTP JSON API managing the databases of all the providers and engines.
+The API is described by the OpenAPI spec served at /v1/openapi.yaml.
+
+The API has no authentication, so it listens on the localhost by default.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		showSecrets, err := cmd.Flags().GetBool("show-secrets")
+		if err != nil {
+			exit("get show-secrets flag: ", err)
+		}
+		verbosity, err := cmd.Flags().GetCount("verbose")
+		if err != nil {
+			exit("get verbose flag: ", err)
+		}
+		// the responses are JSON, the log is for the operator of the server
+		op.Configure(op.FormatText, showSecrets, verbosity)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		showSecrets, err := cmd.Flags().GetBool("show-secrets")
+		if err != nil {
+			exit("get show-secrets flag: ", err)
+		}
+		err = client.LoadPresets()
+		if err != nil {
+			exit(err)
+		}
+		s := server.New(server.Config{
+			Timeout:     *serveTimeout,
+			ShowSecrets: showSecrets,
+		})
+		srv := &http.Server{
+			Addr:    *serveAddress,
+			Handler: s.Handler(),
+		}
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			<-stop
+			log.Println("Shutting down")
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Error("shutdown: ", err)
+			}
+			// killing the running operations would leave the databases half-changed
+			log.Println("Waiting for the running operations to finish")
+			s.Wait()
+		}()
+
+		log.Printf("Serving the API on http://%s/v1", *serveAddress)
+		err = srv.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			exit("serve: ", err)
+		}
+		<-done
+	},
+}
+
+var serveAddress *string
+var serveTimeout *time.Duration
+
+func init() {
+	serveAddress = serveCmd.Flags().String("address", "127.0.0.1:8080", "Address to listen on")
+	serveTimeout = serveCmd.Flags().Duration("timeout", 15*time.Minute, "How long the create and modify operations wait for the database to get ready")
+
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+	log "github.com/sirupsen/logrus"
+)
+
+// Configure sets up the logger for the output format. The results and the messages about the done
+// work go to stdout, the warnings, errors and verbose messages go to stderr.
+// Verbosity 1 shows the executed kubectl commands with their timings, 2 adds the output of the failed ones
+func Configure(format string, showSecrets bool, verbosity int) {
+	log.SetFormatter(GetFormatter(format, showSecrets))
+	log.SetOutput(ioutil.Discard)
+	log.StandardLogger().ReplaceHooks(log.LevelHooks{})
+	log.AddHook(&streamHook{stdout: os.Stdout, stderr: os.Stderr})
+
+	switch {
+	case verbosity >= 2:
+		log.SetLevel(log.TraceLevel)
+	case verbosity == 1:
+		log.SetLevel(log.DebugLevel)
+	default:
+		log.SetLevel(log.InfoLevel)
+	}
+	if verbosity > 0 {
+		k8s.Tracer = traceCommand
+	}
+}
+
+// streamHook writes the info entries to stdout and the rest to stderr
+type streamHook struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (h *streamHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *streamHook) Fire(entry *log.Entry) error {
+	b, err := entry.Logger.Formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	w := h.stderr
+	if entry.Level == log.InfoLevel {
+		w = h.stdout
+	}
+	_, err = w.Write(b)
+
+	return err
+}
+
+func traceCommand(cmd string, args []string, output []byte, took time.Duration, err error) {
+	log.Debugf("%s %s (%s)", cmd, strings.Join(args, " "), took.Round(time.Millisecond))
+	if err != nil {
+		log.Tracef("%s failed: %v: %s", cmd, err, strings.TrimSpace(string(output)))
+	}
+}
@@ -3,35 +3,52 @@ package output
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/pb"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
 )
 
-func GetFormatter(format string) log.Formatter {
-	switch format {
-	case "json":
-		return &log.JSONFormatter{
+// GetFormatter returns the formatter for the given output format.
+// The messages are written in JSON with the jsonpath and go-template formats, the templates
+// are applied only to the results written by PrintResult.
+// Passwords and other secret values are masked unless showSecrets is set
+func GetFormatter(format string, showSecrets bool) log.Formatter {
+	var f log.Formatter
+	switch FormatName(format) {
+	case FormatJSON, FormatJSONPath, FormatGoTemplate:
+		f = &log.JSONFormatter{
 			DisableTimestamp: true,
 			PrettyPrint:      true,
 		}
+	case FormatYAML:
+		f = &yamlFormatter{}
 
 	default:
-		return &cliTextFormatter{log.TextFormatter{}}
+		f = &cliTextFormatter{TextFormatter: log.TextFormatter{}, showSecrets: showSecrets}
 	}
+
+	if showSecrets {
+		return f
+	}
+	return &redactFormatter{f}
 }
 
 func GetDotprinter(format string) pb.ProgressBar {
-	switch format {
-	case "json":
+	if IsStructured(format) {
 		return pb.NewNoOp()
-	default:
-		return pb.NewDotPrinter()
 	}
+
+	return pb.NewDotPrinter()
 }
 
 type cliTextFormatter struct {
 	log.TextFormatter
+	// showSecrets prints the databases with the passwords, String of them masks the passwords
+	showSecrets bool
 }
 
 func (f *cliTextFormatter) Format(entry *log.Entry) ([]byte, error) {
@@ -42,8 +59,12 @@ func (f *cliTextFormatter) Format(entry *log.Entry) ([]byte, error) {
 	} else {
 		b = &bytes.Buffer{}
 	}
-	if entry.Level == log.ErrorLevel {
+	switch entry.Level {
+	case log.ErrorLevel:
 		b.WriteString("[Error] " + entry.Message)
+	case log.DebugLevel, log.TraceLevel:
+		b.WriteString("[" + strings.Title(entry.Level.String()) + "] " + entry.Message + "\n")
+		return b.Bytes(), nil
 	}
 	if entry.Message != "" && entry.Level != log.ErrorLevel && entry.Message != "information" {
 		b.WriteString(entry.Message + "\n")
@@ -55,8 +76,33 @@ func (f *cliTextFormatter) Format(entry *log.Entry) ([]byte, error) {
 	}
 
 	for _, v := range entry.Data {
+		if db, ok := v.(dbaas.DB); ok && f.showSecrets {
+			v = db.WithSecrets()
+		}
 		fmt.Fprint(b, v)
 	}
 	b.WriteString("\n")
 	return b.Bytes(), nil
 }
+
+// yamlFormatter writes the entry the same way as the JSON formatter but in YAML
+type yamlFormatter struct{}
+
+func (f *yamlFormatter) Format(entry *log.Entry) ([]byte, error) {
+	data := make(log.Fields, len(entry.Data)+2)
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		data[k] = v
+	}
+	data["level"] = entry.Level.String()
+	data["msg"] = entry.Message
+
+	b, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal entry")
+	}
+
+	return append([]byte("---\n"), b...), nil
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	log "github.com/sirupsen/logrus"
+)
+
+var secrets = struct {
+	sync.RWMutex
+	values map[string]struct{}
+}{values: make(map[string]struct{})}
+
+// AddSecret registers the value which must never appear in the output
+func AddSecret(value string) {
+	if len(value) == 0 {
+		return
+	}
+	secrets.Lock()
+	secrets.values[value] = struct{}{}
+	secrets.Unlock()
+}
+
+// Redact replaces all registered secret values in s with the mask
+func Redact(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+	for v := range secrets.values {
+		s = strings.Replace(s, v, dbaas.SecretMask, -1)
+		// the value may be escaped in the json output
+		if esc, err := json.Marshal(v); err == nil {
+			s = strings.Replace(s, string(esc[1:len(esc)-1]), dbaas.SecretMask, -1)
+		}
+	}
+
+	return s
+}
+
+// redactFormatter masks passwords and registered secret values before passing the entry to the real formatter
+type redactFormatter struct {
+	log.Formatter
+}
+
+func (f *redactFormatter) Format(entry *log.Entry) ([]byte, error) {
+	e := *entry
+	e.Data = make(log.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		e.Data[k] = redactValue(v)
+	}
+	e.Message = Redact(entry.Message)
+
+	b, err := f.Formatter.Format(&e)
+	if err != nil {
+		return b, err
+	}
+
+	return []byte(Redact(string(b))), nil
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case dbaas.DB:
+		AddSecret(val.Pass)
+		return val.Redacted()
+	case []dbaas.DB:
+		list := make([]dbaas.DB, len(val))
+		for i, db := range val {
+			AddSecret(db.Pass)
+			list[i] = db.Redacted()
+		}
+		return list
+	case error:
+		return Redact(val.Error())
+	case string:
+		return Redact(val)
+	}
+
+	return v
+}
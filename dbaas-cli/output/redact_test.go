@@ -0,0 +1,70 @@
+package output_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+func TestRedact(t *testing.T) {
+	const pass = `s3cr"et\pass`
+	const flagPass = "fl4gP4ssw0rd"
+	op.AddSecret(flagPass)
+
+	db := dbaas.DB{
+		ResourceName: "test",
+		User:         "root",
+		Pass:         pass,
+		Status:       dbaas.StateReady,
+		Message:      "mysql -h test -uroot -p" + pass,
+	}
+
+	entries := []func(l *log.Logger){
+		func(l *log.Logger) { l.WithField("database", db).Info("information") },
+		func(l *log.Logger) { l.WithField("database-list", []dbaas.DB{db}).Info("information") },
+		func(l *log.Logger) { l.Error("create db: ", errors.New("wrong password "+flagPass)) },
+		func(l *log.Logger) { l.WithField("err", errors.New("wrong password "+flagPass)).Info("information") },
+	}
+
+	if s := fmt.Sprint(db); strings.Contains(s, pass) {
+		t.Errorf("database details contain the password:\n%s", s)
+	}
+	if s := db.WithSecrets().String(); !strings.Contains(s, pass) {
+		t.Errorf("database details should contain the password with secrets shown:\n%s", s)
+	}
+
+	for _, format := range []string{"text", "json", "yaml"} {
+		for i, entry := range entries {
+			out := logEntry(op.GetFormatter(format, false), entry)
+			for _, secret := range []string{pass, `s3cr\"et\\pass`, flagPass} {
+				if strings.Contains(out, secret) {
+					t.Errorf("%s output of entry %d contains the secret %q:\n%s", format, i, secret, out)
+				}
+			}
+			if !strings.Contains(out, dbaas.SecretMask) {
+				t.Errorf("%s output of entry %d has no mask:\n%s", format, i, out)
+			}
+		}
+
+		out := logEntry(op.GetFormatter(format, true), entries[0])
+		if !strings.Contains(out, "s3cr") {
+			t.Errorf("%s output should contain the password with secrets shown:\n%s", format, out)
+		}
+	}
+}
+
+func logEntry(f log.Formatter, entry func(l *log.Logger)) string {
+	buf := &bytes.Buffer{}
+	l := log.New()
+	l.SetOutput(buf)
+	l.SetFormatter(f)
+	entry(l)
+	return buf.String()
+}
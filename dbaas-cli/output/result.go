@@ -0,0 +1,190 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats given with the -o flag. The jsonpath and go-template formats are followed
+// by "=" and the template, e.g. -o jsonpath='{.resourceEndpoint}'
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatYAML       = "yaml"
+	FormatJSONPath   = "jsonpath"
+	FormatGoTemplate = "go-template"
+)
+
+// APIVersion is the version of the schema of the result documents. The fields are only added
+// within the version, they are never renamed or removed
+const APIVersion = "dbaas.percona.com/v1"
+
+// Kinds of the results. The json and yaml documents have the "apiVersion" and "kind" fields
+// and the result itself in the field named after the kind:
+//
+//	apiVersion: dbaas.percona.com/v1
+//	kind: Database
+//	database:
+//	  resourceName: my-cluster
+//	  ...
+//
+// The jsonpath and go-template templates are executed on the result itself: the database
+// object or the array of them. The field names are the ones of the json document
+const (
+	KindDatabase     = "Database"
+	KindDatabaseList = "DatabaseList"
+)
+
+var kindFields = map[string]string{
+	KindDatabase:     "database",
+	KindDatabaseList: "database-list",
+}
+
+// FormatName returns the name of the output format without the template
+func FormatName(format string) string {
+	return strings.SplitN(format, "=", 2)[0]
+}
+
+// IsStructured tells if the output format is for scripts rather than humans
+func IsStructured(format string) bool {
+	return FormatName(format) != FormatText
+}
+
+// ValidateFormat checks the output format is known and it has a template if it needs one
+func ValidateFormat(format string) error {
+	switch FormatName(format) {
+	case FormatText, FormatJSON, FormatYAML:
+		if strings.Contains(format, "=") {
+			return errors.Errorf("%s output format doesn't take a template", FormatName(format))
+		}
+	case FormatJSONPath, FormatGoTemplate:
+		if len(formatTemplate(format)) == 0 {
+			return errors.Errorf("%s output format requires a template, e.g. -o %s='{.resourceName}'", FormatName(format), FormatName(format))
+		}
+	default:
+		return errors.Errorf(`unknown output format %q, it can be "text", "json", "yaml", "jsonpath=..." or "go-template=..."`, format)
+	}
+
+	return nil
+}
+
+// PrintResult writes the result of the kind in the structured output format to w.
+// Passwords and other secret values are masked unless showSecrets is set
+func PrintResult(w io.Writer, format, kind string, result interface{}, showSecrets bool) error {
+	field, ok := kindFields[kind]
+	if !ok {
+		return errors.Errorf("unknown result kind %q", kind)
+	}
+	if !showSecrets {
+		result = redactValue(result)
+	}
+
+	var b []byte
+	var err error
+	switch FormatName(format) {
+	case FormatJSON:
+		b, err = json.MarshalIndent(document(kind, field, result), "", "  ")
+		b = append(b, '\n')
+	case FormatYAML:
+		b, err = yaml.Marshal(document(kind, field, result))
+	case FormatJSONPath:
+		b, err = executeJSONPath(formatTemplate(format), result)
+	case FormatGoTemplate:
+		b, err = executeGoTemplate(formatTemplate(format), result)
+	default:
+		return errors.Errorf("%s isn't a structured output format", format)
+	}
+	if err != nil {
+		return err
+	}
+	if !showSecrets {
+		b = []byte(Redact(string(b)))
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+func document(kind, field string, result interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": APIVersion,
+		"kind":       kind,
+		field:        result,
+	}
+}
+
+func formatTemplate(format string) string {
+	parts := strings.SplitN(format, "=", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[1]
+}
+
+// generic returns the result as the maps and slices of its json form, so the templates
+// refer to the fields by their json names
+func generic(result interface{}) (interface{}, error) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal result")
+	}
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal result")
+	}
+
+	return v, nil
+}
+
+func executeJSONPath(tmpl string, result interface{}) ([]byte, error) {
+	// the braces can be omitted for a single expression, e.g. -o jsonpath=.resourceName
+	if !strings.Contains(tmpl, "{") {
+		tmpl = "{" + tmpl + "}"
+	}
+	j := jsonpath.New("output")
+	err := j.Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse jsonpath template")
+	}
+	v, err := generic(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	err = j.Execute(&b, v)
+	if err != nil {
+		return nil, errors.Wrap(err, "execute jsonpath template")
+	}
+	b.WriteString("\n")
+
+	return b.Bytes(), nil
+}
+
+func executeGoTemplate(tmpl string, result interface{}) ([]byte, error) {
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse go-template")
+	}
+	v, err := generic(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, v)
+	if err != nil {
+		return nil, errors.Wrap(err, "execute go-template")
+	}
+
+	return b.Bytes(), nil
+}
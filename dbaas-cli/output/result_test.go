@@ -0,0 +1,85 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	op "github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/output"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+func TestPrintResult(t *testing.T) {
+	const pass = "r3sultP4ss"
+	db := dbaas.DB{
+		ResourceName:     "orders",
+		ResourceEndpoint: "orders-proxysql.default.pxc.svc.local",
+		Pass:             pass,
+		Status:           dbaas.StateReady,
+	}
+	list := []dbaas.DB{db, {ResourceName: "billing", Status: dbaas.StateInit}}
+
+	printResult := func(format, kind string, v interface{}) string {
+		var b bytes.Buffer
+		err := op.PrintResult(&b, format, kind, v, false)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if strings.Contains(b.String(), pass) {
+			t.Errorf("%s output contains the password:\n%s", format, b.String())
+		}
+		return b.String()
+	}
+
+	var doc struct {
+		APIVersion string     `json:"apiVersion"`
+		Kind       string     `json:"kind"`
+		List       []dbaas.DB `json:"database-list"`
+	}
+	err := json.Unmarshal([]byte(printResult("json", op.KindDatabaseList, list)), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.APIVersion != op.APIVersion || doc.Kind != op.KindDatabaseList || len(doc.List) != 2 {
+		t.Errorf("unexpected json document %+v", doc)
+	}
+
+	out := printResult("yaml", op.KindDatabase, db)
+	for _, s := range []string{"apiVersion: " + op.APIVersion, "kind: Database", "database:", "  resourceName: orders"} {
+		if !strings.Contains(out, s+"\n") {
+			t.Errorf("yaml output doesn't contain %q:\n%s", s, out)
+		}
+	}
+
+	for format, want := range map[string]string{
+		"jsonpath={.resourceEndpoint}":               db.ResourceEndpoint + "\n",
+		"jsonpath=.status":                           "ready\n",
+		"go-template={{.resourceName}}/{{.status}}":  "orders/ready",
+		"go-template={{.pass}}":                      dbaas.SecretMask,
+		"jsonpath={range [*]}{.resourceName} {end}":  "orders billing \n",
+		"go-template={{range .}}{{.status}},{{end}}": "ready,initializing,",
+	} {
+		var v interface{} = db
+		kind := op.KindDatabase
+		if strings.Contains(format, "range") {
+			v, kind = list, op.KindDatabaseList
+		}
+		if got := printResult(format, kind, v); got != want {
+			t.Errorf("%s: got %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, f := range []string{"text", "json", "yaml", "jsonpath={.status}", "go-template={{.status}}"} {
+		if err := op.ValidateFormat(f); err != nil {
+			t.Errorf("%s: unexpected error: %v", f, err)
+		}
+	}
+	for _, f := range []string{"xml", "json=x", "jsonpath", "go-template="} {
+		if err := op.ValidateFormat(f); err == nil {
+			t.Errorf("%s: expected error", f)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package pb
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"time"
 )
@@ -11,6 +12,7 @@ type ProgressBar interface {
 	Stop(message string)
 }
 
+// DotPrinter shows the progress on stderr, so it doesn't mix with the results on stdout
 type DotPrinter struct {
 	stop chan string
 	wg   sync.WaitGroup
@@ -37,13 +39,13 @@ func (d *DotPrinter) start(message string) {
 	d.wg.Add(1)
 	tckr := time.NewTicker(time.Second * 5)
 	defer tckr.Stop()
-	fmt.Printf(message)
+	fmt.Fprint(os.Stderr, message)
 	for {
 		select {
 		case <-tckr.C:
-			fmt.Print(".")
+			fmt.Fprint(os.Stderr, ".")
 		case msg := <-d.stop:
-			fmt.Printf("[%s]\n", msg)
+			fmt.Fprintf(os.Stderr, "[%s]\n", msg)
 			d.wg.Done()
 			return
 		}
@@ -0,0 +1,272 @@
+package server
+
+// OpenAPISpec describes the API served by the Server
+const OpenAPISpec = `openapi: 3.0.3
+info:
+  title: Percona DBaaS API
+  description: |
+    Manages the MySQL and MongoDB clusters the way the percona-dbaas CLI does.
+    Creating, modifying and deleting the databases are asynchronous: the response is the operation,
+    which is polled with GET /v1/operations/{id} until its status isn't "running".
+    The finished operations are kept for 24 hours.
+    The API has no authentication, run it on the trusted address only.
+  version: v1
+paths:
+  /v1/databases:
+    get:
+      summary: List the databases of all the providers and engines
+      parameters:
+        - name: labels
+          in: query
+          description: Label selector, e.g. "team=payments,env!=dev"
+          schema:
+            type: string
+        - name: status
+          in: query
+          description: Only the databases in the status, e.g. "ready"
+          schema:
+            type: string
+      responses:
+        "200":
+          description: The databases
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Database"
+        "400":
+          $ref: "#/components/responses/Error"
+    post:
+      summary: Create the database
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/CreateRequest"
+      responses:
+        "202":
+          $ref: "#/components/responses/Operation"
+        "400":
+          $ref: "#/components/responses/Error"
+  /v1/databases/{engine}/{name}:
+    parameters:
+      - name: engine
+        in: path
+        required: true
+        description: The engine, "pxc" or "psmdb"
+        schema:
+          type: string
+      - name: name
+        in: path
+        required: true
+        schema:
+          type: string
+      - name: provider
+        in: query
+        description: The provider, "k8s" by default
+        schema:
+          type: string
+    get:
+      summary: Describe the database
+      responses:
+        "200":
+          description: The database
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Database"
+        "400":
+          $ref: "#/components/responses/Error"
+        "404":
+          $ref: "#/components/responses/Error"
+    patch:
+      summary: Modify the options or upgrade the version of the database
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/ModifyRequest"
+      responses:
+        "202":
+          $ref: "#/components/responses/Operation"
+        "400":
+          $ref: "#/components/responses/Error"
+    delete:
+      summary: Delete the database
+      parameters:
+        - name: preserveData
+          in: query
+          description: Keep the volumes of the database
+          schema:
+            type: boolean
+      responses:
+        "202":
+          $ref: "#/components/responses/Operation"
+        "400":
+          $ref: "#/components/responses/Error"
+  /v1/operations:
+    get:
+      summary: List the operations from the newest to the oldest
+      responses:
+        "200":
+          description: The operations
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Operation"
+  /v1/operations/{id}:
+    get:
+      summary: Get the operation
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: The operation
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Operation"
+        "404":
+          $ref: "#/components/responses/Error"
+  /v1/openapi.yaml:
+    get:
+      summary: This spec
+      responses:
+        "200":
+          description: The OpenAPI spec
+          content:
+            application/yaml: {}
+components:
+  responses:
+    Operation:
+      description: The operation is started
+      content:
+        application/json:
+          schema:
+            $ref: "#/components/schemas/Operation"
+    Error:
+      description: The request failed
+      content:
+        application/json:
+          schema:
+            $ref: "#/components/schemas/Error"
+  schemas:
+    CreateRequest:
+      type: object
+      required: [engine, name]
+      properties:
+        provider:
+          type: string
+          default: k8s
+        engine:
+          type: string
+          description: The engine, "pxc" or "psmdb"
+        name:
+          type: string
+        options:
+          type: string
+          description: The engine options, e.g. "spec.pxc.size=3,spec.proxysql.enabled=false"
+        version:
+          type: string
+        preset:
+          type: string
+        rootPassword:
+          type: string
+        usersSecret:
+          type: string
+        tls:
+          type: string
+          enum: [auto, custom, "off"]
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+    ModifyRequest:
+      type: object
+      properties:
+        options:
+          type: string
+        version:
+          type: string
+    Operation:
+      type: object
+      properties:
+        id:
+          type: string
+        type:
+          type: string
+          enum: [create, modify, delete]
+        provider:
+          type: string
+        engine:
+          type: string
+        name:
+          type: string
+        status:
+          type: string
+          enum: [running, succeeded, failed]
+        error:
+          type: string
+        database:
+          $ref: "#/components/schemas/Database"
+        message:
+          type: string
+        started:
+          type: string
+          format: date-time
+        finished:
+          type: string
+          format: date-time
+    Database:
+      type: object
+      properties:
+        resourceName:
+          type: string
+        resourceEndpoint:
+          type: string
+        size:
+          type: string
+        port:
+          type: integer
+        user:
+          type: string
+        pass:
+          type: string
+          description: Masked unless the server runs with --show-secrets
+        status:
+          type: string
+          enum: [unknown, initializing, ready, error]
+        engine:
+          type: string
+        provider:
+          type: string
+        engineVersion:
+          type: string
+        operatorVersion:
+          type: string
+        paused:
+          type: boolean
+        created:
+          type: string
+          format: date-time
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        message:
+          type: string
+    Error:
+      type: object
+      properties:
+        error:
+          type: string
+`
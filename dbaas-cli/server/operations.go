@@ -0,0 +1,104 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Operation states
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// Operation is the asynchronous change of the database. The clients poll it by the ID
+// until it isn't running anymore
+type Operation struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Provider string `json:"provider"`
+	Engine   string `json:"engine"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	// Database is the state of the database after the create or modify operation
+	Database *dbaas.DB `json:"database,omitempty"`
+	// Message is the result of the delete operation, e.g. where the preserved data is
+	Message  string     `json:"message,omitempty"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// operationTTL is how long the finished operations are kept
+const operationTTL = 24 * time.Hour
+
+// operations keeps the operations in memory, they are lost on the server restart
+type operations struct {
+	sync.RWMutex
+	ops map[string]*Operation
+}
+
+// add adds the operation and evicts the ones finished more than operationTTL ago
+func (o *operations) add(op *Operation) {
+	o.Lock()
+	defer o.Unlock()
+	if o.ops == nil {
+		o.ops = make(map[string]*Operation)
+	}
+	for id, old := range o.ops {
+		if old.Finished != nil && time.Since(*old.Finished) > operationTTL {
+			delete(o.ops, id)
+		}
+	}
+	o.ops[op.ID] = op
+}
+
+// update changes the operation under the lock, so the readers get the consistent copy
+func (o *operations) update(id string, f func(op *Operation)) {
+	o.Lock()
+	defer o.Unlock()
+	if op, ok := o.ops[id]; ok {
+		f(op)
+	}
+}
+
+func (o *operations) get(id string) (Operation, bool) {
+	o.RLock()
+	defer o.RUnlock()
+	op, ok := o.ops[id]
+	if !ok {
+		return Operation{}, false
+	}
+
+	return *op, true
+}
+
+// list returns the operations from the newest to the oldest
+func (o *operations) list() []Operation {
+	o.RLock()
+	defer o.RUnlock()
+	list := make([]Operation, 0, len(o.ops))
+	for _, op := range o.ops {
+		list = append(list, *op)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Started.After(list[j].Started) })
+
+	return list
+}
+
+func newOperationID() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		// the time is unique enough for the in-memory operations
+		return time.Now().Format("20060102150405.000000000")
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,404 @@
+// Package server exposes the dbaas library as the HTTP JSON API. The changes of the databases
+// are asynchronous: they return the operation, which is polled until it is finished.
+// The API is described by the OpenAPI spec served at /v1/openapi.yaml
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-cli/client"
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// DefaultProvider is used when the request doesn't set the provider
+const DefaultProvider = "k8s"
+
+// Config is the configuration of the server
+type Config struct {
+	// Timeout is how long the create and modify operations wait for the database to get ready
+	Timeout time.Duration
+	// PollInterval is how often the database state is checked while waiting, 5 seconds by default
+	PollInterval time.Duration
+	// ShowSecrets puts the passwords into the responses, they are masked otherwise
+	ShowSecrets bool
+}
+
+// Server is the HTTP API of the dbaas library
+type Server struct {
+	conf Config
+	ops  operations
+	// engines keep the state of the cluster they work with, so the calls are serialized
+	engines sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// New returns the server with the given configuration
+func New(conf Config) *Server {
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = 5 * time.Second
+	}
+
+	return &Server{conf: conf}
+}
+
+// CreateRequest is the body of POST /v1/databases
+type CreateRequest struct {
+	Provider string `json:"provider,omitempty"`
+	Engine   string `json:"engine"`
+	Name     string `json:"name"`
+	// Options are the engine options in the "spec.pxc.size=3,spec.proxysql.enabled=false" format
+	Options      string            `json:"options,omitempty"`
+	Version      string            `json:"version,omitempty"`
+	Preset       string            `json:"preset,omitempty"`
+	RootPassword string            `json:"rootPassword,omitempty"`
+	UsersSecret  string            `json:"usersSecret,omitempty"`
+	TLS          string            `json:"tls,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// ModifyRequest is the body of PATCH /v1/databases/{engine}/{name}
+type ModifyRequest struct {
+	Options string `json:"options,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Error is the body of the failed responses
+type Error struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the handler of the API routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/databases", s.handleDatabases)
+	mux.HandleFunc("/v1/databases/", s.handleDatabase)
+	mux.HandleFunc("/v1/operations", s.handleOperations)
+	mux.HandleFunc("/v1/operations/", s.handleOperation)
+	mux.HandleFunc("/v1/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write([]byte(OpenAPISpec))
+	})
+
+	return mux
+}
+
+// Wait waits for the running operations to finish
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Server) handleDatabases(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		filter := dbaas.ListFilter{
+			Labels: r.URL.Query().Get("labels"),
+			Status: dbaas.State(r.URL.Query().Get("status")),
+		}
+		s.engines.Lock()
+		list, errs, err := dbaas.ListAllDB(filter)
+		s.engines.Unlock()
+		if err != nil {
+			writeError(w, client.Validation(err))
+			return
+		}
+		for _, err := range errs {
+			log.Warn("list databases: ", err)
+		}
+		if list == nil {
+			list = []dbaas.DB{}
+		}
+		for i := range list {
+			list[i] = s.redact(list[i])
+		}
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		var req CreateRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			writeError(w, client.Validation(errors.Wrap(err, "decode request")))
+			return
+		}
+		instance, err := createInstance(req)
+		if err != nil {
+			writeError(w, client.Validation(err))
+			return
+		}
+		op := s.start("create", instance, func() (*dbaas.DB, string, error) {
+			err := s.call(func() error { return dbaas.CreateDB(instance) })
+			if err != nil {
+				return nil, "", err
+			}
+			db, err := s.waitReady(instance)
+			return db, "", err
+		})
+		writeJSON(w, http.StatusAccepted, op)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleDatabase(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/databases/"), "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		writeError(w, client.Validation(errors.New("the path should be /v1/databases/{engine}/{name}")))
+		return
+	}
+	instance := dbaas.Instance{
+		Provider: r.URL.Query().Get("provider"),
+		Engine:   parts[0],
+		Name:     parts[1],
+	}
+	if len(instance.Provider) == 0 {
+		instance.Provider = DefaultProvider
+	}
+	err := checkEngine(instance)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var db dbaas.DB
+		err := s.call(func() (err error) {
+			db, err = dbaas.DescribeDB(instance)
+			return err
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.redact(db))
+	case http.MethodPatch:
+		var req ModifyRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			writeError(w, client.Validation(errors.Wrap(err, "decode request")))
+			return
+		}
+		if len(req.Options) == 0 && len(req.Version) == 0 {
+			writeError(w, client.Validation(errors.New("options or version should be set")))
+			return
+		}
+		instance.EngineOptions = req.Options
+		instance.Version = req.Version
+		op := s.start("modify", instance, func() (*dbaas.DB, string, error) {
+			err := s.call(func() error { return dbaas.ModifyDB(instance) })
+			if err != nil {
+				return nil, "", err
+			}
+			db, err := s.waitReady(instance)
+			return db, "", err
+		})
+		writeJSON(w, http.StatusAccepted, op)
+	case http.MethodDelete:
+		preserve := false
+		if v := r.URL.Query().Get("preserveData"); len(v) > 0 {
+			preserve, err = strconv.ParseBool(v)
+			if err != nil {
+				writeError(w, client.Validation(errors.Wrap(err, "parse preserveData")))
+				return
+			}
+		}
+		op := s.start("delete", instance, func() (*dbaas.DB, string, error) {
+			var msg string
+			err := s.call(func() (err error) {
+				msg, err = dbaas.DeleteDB(instance, !preserve)
+				return err
+			})
+			return nil, msg, err
+		})
+		writeJSON(w, http.StatusAccepted, op)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+	}
+}
+
+func (s *Server) handleOperations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.ops.list())
+}
+
+func (s *Server) handleOperation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/v1/operations/")
+	op, ok := s.ops.get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, Error{Error: "operation " + id + " not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, op)
+}
+
+// start runs the operation in background and returns its initial state
+func (s *Server) start(typ string, instance dbaas.Instance, run func() (*dbaas.DB, string, error)) Operation {
+	op := &Operation{
+		ID:       newOperationID(),
+		Type:     typ,
+		Provider: instance.Provider,
+		Engine:   instance.Engine,
+		Name:     instance.Name,
+		Status:   OperationRunning,
+		Started:  time.Now().UTC(),
+	}
+	s.ops.add(op)
+	started := *op
+	log.Infof("operation %s: %s %s/%s/%s started", op.ID, typ, op.Provider, op.Engine, op.Name)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		db, msg, err := run()
+		s.ops.update(op.ID, func(op *Operation) {
+			finished := time.Now().UTC()
+			op.Finished = &finished
+			op.Message = msg
+			if db != nil {
+				redacted := s.redact(*db)
+				op.Database = &redacted
+			}
+			if err != nil {
+				op.Status = OperationFailed
+				op.Error = err.Error()
+				log.Errorf("operation %s: %s %s/%s/%s failed: %v", op.ID, typ, op.Provider, op.Engine, op.Name, err)
+				return
+			}
+			op.Status = OperationSucceeded
+			log.Infof("operation %s: %s %s/%s/%s succeeded", op.ID, typ, op.Provider, op.Engine, op.Name)
+		})
+	}()
+
+	return started
+}
+
+// call calls the engine, the engines aren't safe for the concurrent use
+func (s *Server) call(f func() error) error {
+	s.engines.Lock()
+	defer s.engines.Unlock()
+
+	return f()
+}
+
+// waitReady waits for the database to get ready. The engine is locked only while its state is checked,
+// so the other requests are served in the meantime
+func (s *Server) waitReady(instance dbaas.Instance) (*dbaas.DB, error) {
+	deadline := time.Now().Add(s.conf.Timeout)
+	for {
+		var db dbaas.DB
+		err := s.call(func() (err error) {
+			db, err = dbaas.DescribeDB(instance)
+			return err
+		})
+		if err == nil && db.Status == dbaas.StateReady {
+			return &db, nil
+		}
+		if err == nil && db.Status == dbaas.StateError {
+			return &db, errors.Errorf("database is in %s state", db.Status)
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return nil, errors.Wrap(client.ErrTimeout{}, err.Error())
+			}
+			return &db, client.ErrTimeout{Status: string(db.Status)}
+		}
+		time.Sleep(s.conf.PollInterval)
+	}
+}
+
+func (s *Server) redact(db dbaas.DB) dbaas.DB {
+	if s.conf.ShowSecrets {
+		return db
+	}
+
+	return db.Redacted()
+}
+
+func createInstance(req CreateRequest) (dbaas.Instance, error) {
+	instance := dbaas.Instance{
+		Provider:      req.Provider,
+		Engine:        req.Engine,
+		Name:          req.Name,
+		EngineOptions: req.Options,
+		Version:       req.Version,
+		Preset:        req.Preset,
+		RootPass:      req.RootPassword,
+		UsersSecret:   req.UsersSecret,
+		TLS:           req.TLS,
+		Labels:        req.Labels,
+	}
+	if len(instance.Provider) == 0 {
+		instance.Provider = DefaultProvider
+	}
+	if len(instance.Name) == 0 {
+		return instance, errors.New("name is required")
+	}
+	if len(instance.Engine) == 0 {
+		return instance, errors.New("engine is required")
+	}
+	for k, v := range instance.Labels {
+		err := dbaas.ValidateLabel(k, v)
+		if err != nil {
+			return instance, err
+		}
+	}
+
+	return instance, checkEngine(instance)
+}
+
+func checkEngine(instance dbaas.Instance) error {
+	p, ok := dbaas.Providers[instance.Provider]
+	if !ok {
+		return client.Validation(errors.Errorf("unknown provider %q", instance.Provider))
+	}
+	if _, ok := p.Engines[instance.Engine]; !ok {
+		return client.Validation(errors.Errorf("unknown engine %q of provider %q", instance.Engine, instance.Provider))
+	}
+
+	return nil
+}
+
+// statusCodes are the HTTP status codes of the error types, the same types give the CLI exit codes
+var statusCodes = map[int]int{
+	client.ExitValidation:            http.StatusBadRequest,
+	client.ExitNotFound:              http.StatusNotFound,
+	client.ExitAlreadyExists:         http.StatusConflict,
+	client.ExitTimeout:               http.StatusGatewayTimeout,
+	client.ExitInsufficientResources: http.StatusUnprocessableEntity,
+	client.ExitPermissionDenied:      http.StatusForbidden,
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code, ok := statusCodes[client.ExitCode(err)]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	writeJSON(w, code, Error{Error: err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, Error{Error: "method not allowed"})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Error("write response: ", err)
+	}
+}
@@ -0,0 +1,254 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	dbaas "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// fakeEngine keeps the databases in memory, they are ready as soon as they are created
+type fakeEngine struct {
+	mu  sync.Mutex
+	dbs map[string]dbaas.DB
+	// deletedPVC is the delePVC flag of the databases deleted so far
+	deletedPVC map[string]bool
+}
+
+func (e *fakeEngine) ParseOptions(opts string) error { return nil }
+
+func (e *fakeEngine) CreateDBCluster(instance dbaas.Instance) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.dbs[instance.Name]; ok {
+		return k8s.ErrAlreadyExists{Typ: "fake", Cluster: instance.Name}
+	}
+	e.dbs[instance.Name] = dbaas.DB{
+		ResourceName: instance.Name,
+		Status:       dbaas.StateReady,
+		User:         "root",
+		Pass:         instance.RootPass,
+		Labels:       instance.Labels,
+	}
+
+	return nil
+}
+
+func (e *fakeEngine) DeleteDBCluster(name, opts, version string, delePVC bool) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.dbs[name]; !ok {
+		return "", errors.Wrapf(k8s.ErrNotFound, "get %s", name)
+	}
+	delete(e.dbs, name)
+	e.deletedPVC[name] = delePVC
+
+	return "", nil
+}
+
+func (e *fakeEngine) GetDBCluster(name, opts string) (dbaas.DB, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	db, ok := e.dbs[name]
+	if !ok {
+		return db, errors.Wrapf(k8s.ErrNotFound, "get %s", name)
+	}
+
+	return db, nil
+}
+
+func (e *fakeEngine) GetDBClusterList() ([]dbaas.DB, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var list []dbaas.DB
+	for _, db := range e.dbs {
+		list = append(list, db)
+	}
+
+	return list, nil
+}
+
+func (e *fakeEngine) UpdateDBCluster(name, opts, version string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	db, ok := e.dbs[name]
+	if !ok {
+		return errors.Wrapf(k8s.ErrNotFound, "get %s", name)
+	}
+	db.EngineVersion = version
+	e.dbs[name] = db
+
+	return nil
+}
+
+func (e *fakeEngine) PreCheck(name, opts, version string) ([]string, error) { return nil, nil }
+
+func TestServer(t *testing.T) {
+	engine := &fakeEngine{dbs: make(map[string]dbaas.DB), deletedPVC: make(map[string]bool)}
+	dbaas.RegisterEngine("fake", "db", engine)
+	defer delete(dbaas.Providers, "fake")
+
+	s := New(Config{Timeout: time.Second, PollInterval: 10 * time.Millisecond})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	var op Operation
+	code := request(t, srv, http.MethodPost, "/v1/databases", CreateRequest{
+		Provider:     "fake",
+		Engine:       "db",
+		Name:         "orders",
+		RootPassword: "secret",
+		Labels:       map[string]string{"team": "payments"},
+	}, &op)
+	if code != http.StatusAccepted || op.Status != OperationRunning || len(op.ID) == 0 {
+		t.Fatalf("create: got %d %+v", code, op)
+	}
+	s.Wait()
+	request(t, srv, http.MethodGet, "/v1/operations/"+op.ID, nil, &op)
+	if op.Status != OperationSucceeded || op.Database == nil || op.Database.ResourceName != "orders" || op.Finished == nil {
+		t.Fatalf("create operation: %+v", op)
+	}
+	if op.Database.Pass == "secret" {
+		t.Error("password isn't masked in the operation")
+	}
+
+	var db dbaas.DB
+	code = request(t, srv, http.MethodGet, "/v1/databases/db/orders?provider=fake", nil, &db)
+	if code != http.StatusOK || db.ResourceName != "orders" || db.Pass == "secret" {
+		t.Errorf("describe: got %d %+v", code, db)
+	}
+
+	var list []dbaas.DB
+	request(t, srv, http.MethodGet, "/v1/databases?labels=team%3Dpayments", nil, &list)
+	if len(list) != 1 || list[0].Provider != "fake" {
+		t.Errorf("list: %+v", list)
+	}
+	request(t, srv, http.MethodGet, "/v1/databases?labels=team%3Dbilling", nil, &list)
+	if len(list) != 0 {
+		t.Errorf("list of other team: %+v", list)
+	}
+
+	code = request(t, srv, http.MethodPost, "/v1/databases", CreateRequest{Provider: "fake", Engine: "db", Name: "orders"}, &op)
+	if code != http.StatusAccepted {
+		t.Fatalf("create again: got %d", code)
+	}
+	s.Wait()
+	request(t, srv, http.MethodGet, "/v1/operations/"+op.ID, nil, &op)
+	if op.Status != OperationFailed || len(op.Error) == 0 {
+		t.Errorf("create of the existing database: %+v", op)
+	}
+
+	request(t, srv, http.MethodPatch, "/v1/databases/db/orders?provider=fake", ModifyRequest{Version: "8.0"}, &op)
+	s.Wait()
+	request(t, srv, http.MethodGet, "/v1/operations/"+op.ID, nil, &op)
+	if op.Status != OperationSucceeded || op.Database.EngineVersion != "8.0" {
+		t.Errorf("modify: %+v", op)
+	}
+
+	request(t, srv, http.MethodDelete, "/v1/databases/db/orders?provider=fake", nil, &op)
+	s.Wait()
+	request(t, srv, http.MethodGet, "/v1/operations/"+op.ID, nil, &op)
+	if op.Status != OperationSucceeded || !engine.deletedPVC["orders"] {
+		t.Errorf("delete: %+v, volumes deleted %v", op, engine.deletedPVC["orders"])
+	}
+
+	request(t, srv, http.MethodPost, "/v1/databases", CreateRequest{Provider: "fake", Engine: "db", Name: "kept"}, &op)
+	s.Wait()
+	request(t, srv, http.MethodDelete, "/v1/databases/db/kept?provider=fake&preserveData=true", nil, &op)
+	s.Wait()
+	request(t, srv, http.MethodGet, "/v1/operations/"+op.ID, nil, &op)
+	if op.Status != OperationSucceeded || engine.deletedPVC["kept"] {
+		t.Errorf("delete preserving data: %+v, volumes deleted %v", op, engine.deletedPVC["kept"])
+	}
+
+	var ops []Operation
+	request(t, srv, http.MethodGet, "/v1/operations", nil, &ops)
+	if len(ops) != 6 || ops[0].Type != "delete" {
+		t.Errorf("operations: %+v", ops)
+	}
+
+	var e Error
+	for _, c := range []struct {
+		method, path string
+		body         interface{}
+		code         int
+	}{
+		{http.MethodGet, "/v1/databases/db/orders?provider=fake", nil, http.StatusNotFound},
+		{http.MethodGet, "/v1/databases/nosuch/orders?provider=fake", nil, http.StatusBadRequest},
+		{http.MethodGet, "/v1/databases/db", nil, http.StatusBadRequest},
+		{http.MethodGet, "/v1/operations/nosuch", nil, http.StatusNotFound},
+		{http.MethodPost, "/v1/databases", CreateRequest{Provider: "fake", Engine: "db"}, http.StatusBadRequest},
+		{http.MethodPost, "/v1/databases", CreateRequest{Provider: "fake", Engine: "db", Name: "x", Labels: map[string]string{"app.kubernetes.io/name": "x"}}, http.StatusBadRequest},
+		{http.MethodPatch, "/v1/databases/db/orders?provider=fake", ModifyRequest{}, http.StatusBadRequest},
+		{http.MethodPut, "/v1/databases", nil, http.StatusMethodNotAllowed},
+	} {
+		if code := request(t, srv, c.method, c.path, c.body, &e); code != c.code || len(e.Error) == 0 {
+			t.Errorf("%s %s: got %d %q, want %d", c.method, c.path, code, e.Error, c.code)
+		}
+	}
+}
+
+func TestOperationsEviction(t *testing.T) {
+	var o operations
+	old := time.Now().Add(-operationTTL - time.Minute)
+	o.add(&Operation{ID: "finished", Started: old, Finished: &old})
+	o.add(&Operation{ID: "running", Started: old})
+	o.add(&Operation{ID: "new", Started: time.Now()})
+	if _, ok := o.get("finished"); ok {
+		t.Error("the expired operation is kept")
+	}
+	if _, ok := o.get("running"); !ok {
+		t.Error("the running operation is evicted")
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	var spec struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	err := yaml.Unmarshal([]byte(OpenAPISpec), &spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"/v1/databases", "/v1/databases/{engine}/{name}", "/v1/operations", "/v1/operations/{id}"} {
+		if _, ok := spec.Paths[p]; !ok {
+			t.Errorf("path %s isn't described", p)
+		}
+	}
+}
+
+func request(t *testing.T, srv *httptest.Server, method, path string, body, res interface{}) int {
+	t.Helper()
+	var b bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&b).Encode(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, srv.URL+path, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(res)
+	if err != nil {
+		t.Fatalf("%s %s: decode response: %v", method, path, err)
+	}
+
+	return resp.StatusCode
+}
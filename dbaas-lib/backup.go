@@ -0,0 +1,133 @@
+package dbaas
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BackupSchedule is the backup the operator takes periodically
+type BackupSchedule struct {
+	Name string `json:"name"`
+	// Schedule is the cron expression in the standard 5-field format
+	Schedule string `json:"schedule"`
+	Storage  string `json:"storage"`
+	// Keep is the number of backups kept, zero keeps all of them
+	Keep int `json:"keep,omitempty"`
+	// Disabled schedules are kept in the cluster but backups aren't taken
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// BackupStorage is the storage the backups are written to
+type BackupStorage struct {
+	Name string `json:"name"`
+	// Type is "s3" or "filesystem"
+	Type string `json:"type"`
+
+	Bucket      string `json:"bucket,omitempty"`
+	Region      string `json:"region,omitempty"`
+	EndpointURL string `json:"endpointUrl,omitempty"`
+	// CredentialsSecret is the secret with S3 credentials. If it is empty the secret
+	// is created from KeyID and Key
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	KeyID             string `json:"-"`
+	Key               string `json:"-"`
+
+	// Size and StorageClass are of the volume of the filesystem storage
+	Size         string `json:"size,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    []string
+}{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// ValidateCron checks the cron expression in the standard 5-field format the operators use.
+// Macros like @daily are accepted as well
+func ValidateCron(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if m, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = m
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return errors.Errorf("cron expression %q should have %d fields, it has %d", expr, len(cronFields), len(fields))
+	}
+
+	for i, f := range fields {
+		for _, part := range strings.Split(f, ",") {
+			err := checkCronPart(part, cronFields[i].min, cronFields[i].max, cronFields[i].names)
+			if err != nil {
+				return errors.Wrapf(err, "%s field of %q", cronFields[i].name, expr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkCronPart checks "*", "n", "n-m" optionally followed by "/step"
+func checkCronPart(part string, min, max int, names []string) error {
+	rng := part
+	if i := strings.Index(part, "/"); i >= 0 {
+		rng = part[:i]
+		step, err := strconv.Atoi(part[i+1:])
+		if err != nil || step < 1 {
+			return errors.Errorf("invalid step in %q", part)
+		}
+	}
+	if rng == "*" {
+		return nil
+	}
+
+	bounds := strings.SplitN(rng, "-", 2)
+	vals := make([]int, len(bounds))
+	for i, b := range bounds {
+		v, err := cronValue(b, min, names)
+		if err != nil {
+			return err
+		}
+		if v < min || v > max {
+			return errors.Errorf("%d is out of range %d-%d", v, min, max)
+		}
+		vals[i] = v
+	}
+	if len(vals) == 2 && vals[0] > vals[1] {
+		return errors.Errorf("invalid range %q", rng)
+	}
+
+	return nil
+}
+
+func cronValue(s string, min int, names []string) (int, error) {
+	for i, n := range names {
+		if strings.EqualFold(s, n) {
+			return min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q", s)
+	}
+
+	return v, nil
+}
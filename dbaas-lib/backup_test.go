@@ -0,0 +1,38 @@
+package dbaas
+
+import "testing"
+
+func TestValidateCron(t *testing.T) {
+	for _, expr := range []string{
+		"0 0 * * *",
+		"*/15 * * * *",
+		"0 2 * * 1-5",
+		"30 1,13 1 */2 sun",
+		"0 0 1 jan,jul *",
+		"5-55/10 0-23/2 * * 7",
+		"@daily",
+	} {
+		if err := ValidateCron(expr); err != nil {
+			t.Errorf("%q: unexpected error: %v", expr, err)
+		}
+	}
+
+	for _, expr := range []string{
+		"",
+		"0 0 * *",
+		"0 0 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"@every 5m",
+	} {
+		if err := ValidateCron(expr); err == nil {
+			t.Errorf("%q: expected error", expr)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package dbaas
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
 
 type State string
 
@@ -11,6 +18,25 @@ const (
 	StateError   State = "error"
 )
 
+// AnnotationPrefix is the prefix of the cluster object annotations set by the dbaas tools
+const AnnotationPrefix = "dbaas.percona.com/"
+
+// DBAnnotations returns the annotations with the AnnotationPrefix
+func DBAnnotations(annotations map[string]string) map[string]string {
+	var res map[string]string
+	for k, v := range annotations {
+		if !strings.HasPrefix(k, AnnotationPrefix) {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]string)
+		}
+		res[k] = v
+	}
+
+	return res
+}
+
 type DB struct {
 	ResourceName     string `json:"resourceName,omitempty"`
 	ResourceEndpoint string `json:"resourceEndpoint,omitempty"`
@@ -21,10 +47,97 @@ type DB struct {
 	Status           State  `json:"status,omitempty"`
 	Engine           string `json:"engine,omitempty"`
 	Provider         string `json:"provider,omitempty"`
+	CAFingerprint    string `json:"caFingerprint,omitempty"`
+	Preset           string `json:"preset,omitempty"`
+	Monitoring       string `json:"monitoring,omitempty"`
 	Message          string `json:"message,omitempty"`
+	EngineVersion    string `json:"engineVersion,omitempty"`
+	OperatorVersion  string `json:"operatorVersion,omitempty"`
+	Paused           bool   `json:"paused,omitempty"`
+
+	// Components are the sizes and ready counts of pxc and proxysql or of each mongo replset
+	Components      []ComponentStatus `json:"components,omitempty"`
+	Created         time.Time         `json:"created"`
+	BackupSchedules []BackupSchedule  `json:"backupSchedules,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	// Annotations are the annotations of the cluster object with the AnnotationPrefix
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Events is the timeline of the cluster objects events, it is filled on request
+	Events []Event `json:"events,omitempty"`
+}
+
+// Event is the event of the cluster object
+type Event struct {
+	First   time.Time `json:"first"`
+	Last    time.Time `json:"last"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Object  string    `json:"object"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count"`
+	// Problem is the kind of the problem the event reports, e.g. "scheduling", "image-pull" or "volume"
+	Problem string `json:"problem,omitempty"`
+	// Hint tells what can be done about the problem
+	Hint string `json:"hint,omitempty"`
+}
+
+// ComponentStatus is the size and readiness of the cluster component
+type ComponentStatus struct {
+	Name     string    `json:"name"`
+	Size     int32     `json:"size"`
+	Ready    int32     `json:"ready"`
+	Requests Resources `json:"requests,omitempty"`
+}
+
+// SecretMask is shown instead of the secret values
+const SecretMask = "********"
+
+// Age returns the human readable time since the cluster was created
+func (d DB) Age() string {
+	if d.Created.IsZero() {
+		return "<unknown>"
+	}
+
+	return duration.HumanDuration(time.Since(d.Created))
+}
+
+// Ready returns the ready and the total number of the members of the main component, pxc or the first replset
+func (d DB) Ready() string {
+	if len(d.Components) == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d/%d", d.Components[0].Ready, d.Components[0].Size)
+}
+
+// Redacted returns the copy of DB with the password masked
+func (d DB) Redacted() DB {
+	if len(d.Pass) > 0 {
+		d.Message = strings.Replace(d.Message, d.Pass, SecretMask, -1)
+		d.Pass = SecretMask
+	}
+
+	return d
 }
 
+// String returns the human readable details of the database with the password masked,
+// WithSecrets returns the ones showing it
 func (d DB) String() string {
+	return d.Redacted().details()
+}
+
+// WithSecrets returns the human readable details of the database which show the password in plain text
+func (d DB) WithSecrets() fmt.Stringer {
+	return dbWithSecrets(d)
+}
+
+type dbWithSecrets DB
+
+func (d dbWithSecrets) String() string {
+	return DB(d).details()
+}
+
+func (d DB) details() string {
 	provider := ""
 	if len(d.Provider) > 0 {
 		provider = fmt.Sprintf("Provider:          %s", d.Provider)
@@ -53,14 +166,94 @@ func (d DB) String() string {
 	if len(d.Pass) > 0 {
 		pass = fmt.Sprintf("\nPass:              %s", d.Pass)
 	}
+	preset := ""
+	if len(d.Preset) > 0 {
+		preset = fmt.Sprintf("\nPreset:            %s", d.Preset)
+	}
+	monitoring := ""
+	if len(d.Monitoring) > 0 {
+		monitoring = fmt.Sprintf("\nMonitoring:        %s", d.Monitoring)
+	}
+	caFingerprint := ""
+	if len(d.CAFingerprint) > 0 {
+		caFingerprint = fmt.Sprintf("\nCA Fingerprint:    %s", d.CAFingerprint)
+	}
 	status := ""
 	if len(d.Status) > 0 {
 		status = fmt.Sprintf("\nStatus:            %s", d.Status)
+		if d.Paused {
+			status += " (paused)"
+		}
+	}
+	details := ""
+	if len(d.EngineVersion) > 0 {
+		details += fmt.Sprintf("\nEngine Version:    %s", d.EngineVersion)
+	}
+	if len(d.OperatorVersion) > 0 {
+		details += fmt.Sprintf("\nOperator Version:  %s", d.OperatorVersion)
+	}
+	if len(d.Size) > 0 {
+		details += fmt.Sprintf("\nStorage Size:      %s", d.Size)
+	}
+	if !d.Created.IsZero() {
+		details += fmt.Sprintf("\nAge:               %s", d.Age())
+	}
+	if len(d.Labels) > 0 {
+		labels := make([]string, 0, len(d.Labels))
+		for k, v := range d.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		details += fmt.Sprintf("\nLabels:            %s", strings.Join(labels, ","))
+	}
+	for _, c := range d.Components {
+		details += fmt.Sprintf("\nComponent %-8s %d/%d ready", c.Name+":", c.Ready, c.Size)
+	}
+	for _, b := range d.BackupSchedules {
+		disabled := ""
+		if b.Disabled {
+			disabled = ", disabled"
+		}
+		details += fmt.Sprintf("\nBackup Schedule:   %s \"%s\" to %s%s", b.Name, b.Schedule, b.Storage, disabled)
 	}
 	message := ""
 	if len(d.Message) > 0 {
 		message = fmt.Sprintf("\n\n%s\n", d.Message)
 	}
 
-	return provider + engine + resourceName + resourceEndpoint + port + user + pass + status + message
+	return provider + engine + resourceName + resourceEndpoint + port + user + pass + preset + monitoring + caFingerprint + status + details + message + eventsTimeline(d.Events)
+}
+
+// eventsTimeline formats the events one per line. Problems are marked and followed by the hints
+func eventsTimeline(events []Event) string {
+	if len(events) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nEvents:\n")
+	for _, e := range events {
+		mark := " "
+		if len(e.Problem) > 0 {
+			mark = "!"
+		}
+		count := ""
+		if e.Count > 1 {
+			count = fmt.Sprintf(" (x%d)", e.Count)
+		}
+		fmt.Fprintf(&b, "%s %s  %-8s %-20s %s%s: %s\n", mark, e.Last.Local().Format("2006-01-02 15:04:05"), e.Type, e.Reason, e.Object, count, e.Message)
+	}
+
+	hints := make(map[string]bool)
+	for _, e := range events {
+		if len(e.Hint) > 0 && !hints[e.Hint] {
+			hints[e.Hint] = true
+			fmt.Fprintf(&b, "\n! %s problem: %s", e.Problem, e.Hint)
+		}
+	}
+	if len(hints) > 0 {
+		b.WriteString("\n")
+	}
+
+	return b.String()
 }
@@ -1,6 +1,9 @@
 package dbaas
 
 import (
+	"io"
+	"time"
+
 	"github.com/pkg/errors"
 )
 
@@ -12,9 +15,41 @@ type Instance struct {
 	DiskSize      string
 	EngineOptions string
 	RootPass      string
-	Version       string
+	// UsersSecret is the name of a pre-provisioned secret with the cluster users credentials.
+	// If it is empty the secret is derived from the cluster name and created if needed
+	UsersSecret string
+	// TLS is the TLS mode for client and intra-cluster connections: TLSAuto, TLSCustom or TLSOff (default)
+	TLS string
+	// TLSSecret and TLSInternalSecret are the names of secrets with certificates for TLSCustom mode.
+	// TLSInternalSecret defaults to TLSSecret
+	TLSSecret         string
+	TLSInternalSecret string
+	// Encryption enables data-at-rest encryption with the key generated for the cluster
+	Encryption bool
+	// Preset is the name of the sizing preset. The options given in EngineOptions override it
+	Preset string
+	// FromPreserved is the name of the deleted cluster the data of which is used by the new one
+	FromPreserved string
+	// Labels are set on the cluster custom resource and its pods
+	Labels map[string]string
+	// KeepBackupSchedules keeps the backup schedules of the source cluster in the clone. They are removed
+	// by default, otherwise the clone would write its backups into the storages of the source
+	KeepBackupSchedules bool
+	// Annotations are set on the cluster custom resource. The keys should have the AnnotationPrefix
+	// to be returned in DB.Annotations
+	Annotations map[string]string
+	Version     string
 }
 
+const (
+	// TLSAuto issues certificates with cert-manager if it is installed or with the self-signed CA otherwise
+	TLSAuto = "auto"
+	// TLSCustom uses the certificates from the secrets provided by user
+	TLSCustom = "custom"
+	// TLSOff leaves the cluster without TLS configured
+	TLSOff = "off"
+)
+
 // CreateDB creates DB resource using name, provider, engine and options given in 'instance' object. The default value provider=k8s, engine=pxc
 func CreateDB(instance Instance) error {
 	err := checkProviderAndEngine(instance)
@@ -22,7 +57,13 @@ func CreateDB(instance Instance) error {
 		return err
 	}
 
-	err = Providers[instance.Provider].Engines[instance.Engine].CreateDBCluster(instance.Name, instance.EngineOptions, instance.RootPass, instance.Version)
+	if len(instance.FromPreserved) > 0 {
+		if _, ok := Providers[instance.Provider].Engines[instance.Engine].(PreservedDataManager); !ok {
+			return errNotSupported(instance, "creating from preserved data")
+		}
+	}
+
+	err = Providers[instance.Provider].Engines[instance.Engine].CreateDBCluster(instance)
 	if err != nil {
 		return err
 	}
@@ -54,6 +95,22 @@ func DescribeDB(instance Instance) (DB, error) {
 	return Providers[instance.Provider].Engines[instance.Engine].GetDBCluster(instance.Name, instance.EngineOptions)
 }
 
+// ListEvents returns the timeline of the events of the DB resource given in 'instance' object and its pods,
+// volume claims and services
+func ListEvents(instance Instance) ([]Event, error) {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	el, ok := Providers[instance.Provider].Engines[instance.Engine].(EventsLister)
+	if !ok {
+		return nil, errNotSupported(instance, "events")
+	}
+
+	return el.ListEvents(instance.Name)
+}
+
 func ListDB(instance Instance) ([]DB, error) {
 	err := checkProviderAndEngine(instance)
 	if err != nil {
@@ -63,21 +120,332 @@ func ListDB(instance Instance) ([]DB, error) {
 	return Providers[instance.Provider].Engines[instance.Engine].GetDBClusterList()
 }
 
-func DeleteDB(instance Instance, saveData bool) (string, error) {
+func DeleteDB(instance Instance, deletePVC bool) (string, error) {
 	err := checkProviderAndEngine(instance)
 	if err != nil {
 		return "", err
 	}
 
-	return Providers[instance.Provider].Engines[instance.Engine].DeleteDBCluster(instance.Name, instance.EngineOptions, instance.Version, saveData)
+	return Providers[instance.Provider].Engines[instance.Engine].DeleteDBCluster(instance.Name, instance.EngineOptions, instance.Version, deletePVC)
+}
+
+// Scale is the desired number of the cluster members
+type Scale struct {
+	// Replicas is the number of database members: PXC nodes or replset members
+	Replicas int32
+	// Proxies is the number of proxy pods, zero keeps the current number
+	Proxies int32
+	// Replset is the name of the psmdb replset to scale, all replsets are scaled if it is empty
+	Replset string
+	// Force allows an even number of members, which can't keep the quorum in case of the network split
+	Force bool
+	// Wait is how long to wait for the new members to become ready, zero means don't wait
+	Wait time.Duration
+}
+
+// Restore is the point the cluster data is restored to
+type Restore struct {
+	// Backup is the name of the backup to restore. If it is empty the latest backup
+	// completed before ToTime is used
+	Backup string
+	// ToTime is the point in time to recover the data to. Point-in-time recovery isn't supported,
+	// so the restore fails if the backup completed before ToTime unless AcceptDataLoss is set
+	ToTime time.Time
+	// AcceptDataLoss allows restoring the backup completed before ToTime, the changes made after it are lost
+	AcceptDataLoss bool
+	// Wait is how long to wait for the restore to complete, zero means don't wait
+	Wait time.Duration
+}
+
+// Backup is the successful backup of the cluster
+type Backup struct {
+	Name      string
+	Completed time.Time
+}
+
+// SelectBackup returns the latest successful backup of the DB resource completed not after the time,
+// it is the backup RestoreDB restores to the time
+func SelectBackup(instance Instance, toTime time.Time) (Backup, error) {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	r, ok := Providers[instance.Provider].Engines[instance.Engine].(Restorer)
+	if !ok {
+		return Backup{}, errNotSupported(instance, "restore")
+	}
+
+	return r.SelectBackup(instance.Name, toTime)
+}
+
+// RestoreDB restores the data of the DB resource given in 'instance' object from the backup.
+// The returned message tells what data is restored if it isn't as of restore.ToTime
+func RestoreDB(instance Instance, restore Restore) (string, error) {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return "", err
+	}
+	if len(restore.Backup) == 0 && restore.ToTime.IsZero() {
+		return "", errors.New("backup name or the time to restore to is required")
+	}
+
+	r, ok := Providers[instance.Provider].Engines[instance.Engine].(Restorer)
+	if !ok {
+		return "", errNotSupported(instance, "restore")
+	}
+
+	return r.RestoreDBCluster(instance.Name, restore)
+}
+
+// EnableMonitoring sets up the PMM client for the DB resource given in 'instance' object
+func EnableMonitoring(instance Instance, m Monitoring) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	mon, ok := Providers[instance.Provider].Engines[instance.Engine].(Monitor)
+	if !ok {
+		return errNotSupported(instance, "monitoring")
+	}
+
+	return mon.EnableMonitoring(instance.Name, m)
+}
+
+// DisableMonitoring removes the PMM client from the DB resource given in 'instance' object
+func DisableMonitoring(instance Instance, wait time.Duration) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	mon, ok := Providers[instance.Provider].Engines[instance.Engine].(Monitor)
+	if !ok {
+		return errNotSupported(instance, "monitoring")
+	}
+
+	return mon.DisableMonitoring(instance.Name, wait)
+}
+
+// CheckHealth checks the DB resource given in 'instance' object and its pods, volumes and disruption budgets
+func CheckHealth(instance Instance) (Health, error) {
+	health := Health{Cluster: instance.Name}
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return health, err
+	}
+
+	hc, ok := Providers[instance.Provider].Engines[instance.Engine].(HealthChecker)
+	if !ok {
+		return health, errNotSupported(instance, "health check")
+	}
+	health.Checks, err = hc.CheckHealth(instance.Name)
+	if err != nil {
+		return health, err
+	}
+	health.Status = Verdict(health.Checks)
+
+	return health, nil
+}
+
+// Logs selects the logs of the cluster to stream
+type Logs struct {
+	// Component is the cluster component, e.g. "pxc" or "operator". Empty means all the components
+	Component string
+	// Follow keeps streaming the new lines
+	Follow bool
+	// Since limits the logs to the newer lines, zero means all the lines
+	Since time.Duration
+}
+
+// StreamLogs writes the logs of the DB resource given in 'instance' object to w
+func StreamLogs(instance Instance, logs Logs, w io.Writer) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	ls, ok := Providers[instance.Provider].Engines[instance.Engine].(LogStreamer)
+	if !ok {
+		return errNotSupported(instance, "logs")
+	}
+
+	return ls.StreamLogs(instance.Name, logs, w)
+}
+
+// ScaleDB changes the number of members of the DB resource given in 'instance' object
+func ScaleDB(instance Instance, scale Scale) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	s, ok := Providers[instance.Provider].Engines[instance.Engine].(Scaler)
+	if !ok {
+		return errNotSupported(instance, "scaling")
+	}
+
+	return s.ScaleDBCluster(instance.Name, scale)
+}
+
+// ResizeStorage expands the volumes of the DB resource given in 'instance' object to the size.
+// If wait isn't zero it waits for the volumes to be resized. The returned message tells
+// if the pods have to be restarted to finish the resize
+func ResizeStorage(instance Instance, size string, wait time.Duration) (string, error) {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return "", err
+	}
+
+	r, ok := Providers[instance.Provider].Engines[instance.Engine].(StorageResizer)
+	if !ok {
+		return "", errNotSupported(instance, "storage resize")
+	}
+
+	return r.ResizeStorage(instance.Name, size, wait)
+}
+
+// RotateEncryptionKey replaces the data-at-rest encryption key of the DB resource given in 'instance' object
+func RotateEncryptionKey(instance Instance) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	r, ok := Providers[instance.Provider].Engines[instance.Engine].(EncryptionKeyRotator)
+	if !ok {
+		return errNotSupported(instance, "encryption key rotation")
+	}
+
+	return r.RotateEncryptionKey(instance.Name)
+}
+
+// CloneDB creates the DB resource given in 'target' object with the options and the data of the source one.
+// The options given in the target override the ones of the source. The labels of the source aren't copied. It waits up to timeout for every step of cloning
+func CloneDB(source string, target Instance, timeout time.Duration) error {
+	err := checkProviderAndEngine(target)
+	if err != nil {
+		return err
+	}
+
+	c, ok := Providers[target.Provider].Engines[target.Engine].(Cloner)
+	if !ok {
+		return errNotSupported(target, "cloning")
+	}
+
+	return c.CloneDBCluster(source, target, timeout)
+}
+
+// AddBackupSchedule adds the scheduled backup to the DB resource given in 'instance' object.
+// If storage isn't nil it is added to the resource as well
+func AddBackupSchedule(instance Instance, schedule BackupSchedule, storage *BackupStorage) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+	err = ValidateCron(schedule.Schedule)
+	if err != nil {
+		return err
+	}
+	if schedule.Keep < 0 {
+		return errors.New("number of backups to keep can't be negative")
+	}
+
+	s, ok := Providers[instance.Provider].Engines[instance.Engine].(BackupScheduler)
+	if !ok {
+		return errNotSupported(instance, "scheduled backup")
+	}
+
+	return s.AddBackupSchedule(instance.Name, schedule, storage)
+}
+
+// ListBackupSchedules returns the scheduled backups and the backup storages of the DB resource given in 'instance' object
+func ListBackupSchedules(instance Instance) ([]BackupSchedule, []BackupStorage, error) {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s, ok := Providers[instance.Provider].Engines[instance.Engine].(BackupScheduler)
+	if !ok {
+		return nil, nil, errNotSupported(instance, "scheduled backup")
+	}
+
+	return s.ListBackupSchedules(instance.Name)
+}
+
+// RemoveBackupSchedule removes the scheduled backup from the DB resource given in 'instance' object.
+// The storage and the backups already taken are kept
+func RemoveBackupSchedule(instance Instance, schedule string) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	s, ok := Providers[instance.Provider].Engines[instance.Engine].(BackupScheduler)
+	if !ok {
+		return errNotSupported(instance, "scheduled backup")
+	}
+
+	return s.RemoveBackupSchedule(instance.Name, schedule)
+}
+
+// ListPreservedData returns the data left by the deleted clusters of the provider and engine given in 'instance' object
+func ListPreservedData(instance Instance) ([]PreservedData, error) {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := Providers[instance.Provider].Engines[instance.Engine].(PreservedDataManager)
+	if !ok {
+		return nil, errNotSupported(instance, "preserving data")
+	}
+
+	return m.ListPreservedData()
+}
+
+// PurgePreservedData deletes the data left by the deleted cluster with the name given in 'instance' object
+func PurgePreservedData(instance Instance) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	m, ok := Providers[instance.Provider].Engines[instance.Engine].(PreservedDataManager)
+	if !ok {
+		return errNotSupported(instance, "preserving data")
+	}
+
+	return m.PurgePreservedData(instance.Name)
+}
+
+// LabelDB sets and removes the labels of the DB resource given in 'instance' object and of its pods
+func LabelDB(instance Instance, set map[string]string, remove []string) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	l, ok := Providers[instance.Provider].Engines[instance.Engine].(Labeler)
+	if !ok {
+		return errNotSupported(instance, "labels")
+	}
+
+	return l.LabelDBCluster(instance.Name, set, remove)
+}
+
+func errNotSupported(instance Instance, feature string) error {
+	return errors.Errorf("%s isn't supported by %s/%s", feature, instance.Provider, instance.Engine)
 }
 
 func checkProviderAndEngine(instance Instance) error {
 	if _, providerOk := Providers[instance.Provider]; !providerOk {
-		return errors.New("wrong provider")
+		return ErrInvalidOption{Path: "provider", Err: errors.Errorf("unknown provider %s", instance.Provider)}
 	}
 	if _, ok := Providers[instance.Provider].Engines[instance.Engine]; !ok {
-		return errors.New("wrong engine")
+		return ErrInvalidOption{Path: "engine", Err: errors.Errorf("unknown engine %s", instance.Engine)}
 	}
 
 	return nil
@@ -0,0 +1,101 @@
+package dbaas
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DiagnosticsFile is the file of the diagnostics bundle
+type DiagnosticsFile struct {
+	Name string
+	Data []byte
+	// Error is why the file couldn't be collected
+	Error string
+}
+
+// DiagnosticsManifest describes the content of the diagnostics bundle
+type DiagnosticsManifest struct {
+	Cluster   string         `json:"cluster"`
+	Engine    string         `json:"engine"`
+	Provider  string         `json:"provider"`
+	Collected time.Time      `json:"collected"`
+	Files     []ManifestFile `json:"files"`
+}
+
+// ManifestFile is the file of the diagnostics bundle listed in the manifest
+type ManifestFile struct {
+	Name  string `json:"name"`
+	Size  int    `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// DiagnosticsDir is the directory the bundle of the cluster collected at the time is unpacked to
+func DiagnosticsDir(cluster string, collected time.Time) string {
+	return cluster + "-diagnostics-" + collected.UTC().Format("20060102T150405Z")
+}
+
+// CollectDiagnostics gathers the diagnostics of the DB resource given in 'instance' object and writes them
+// to w as the gzipped tar archive with manifest.json listing the files
+func CollectDiagnostics(instance Instance, collected time.Time, w io.Writer) error {
+	err := checkProviderAndEngine(instance)
+	if err != nil {
+		return err
+	}
+
+	dc, ok := Providers[instance.Provider].Engines[instance.Engine].(DiagnosticsCollector)
+	if !ok {
+		return errNotSupported(instance, "diagnostics collection")
+	}
+	files, err := dc.CollectDiagnostics(instance.Name)
+	if err != nil {
+		return err
+	}
+
+	manifest := DiagnosticsManifest{
+		Cluster:   instance.Name,
+		Engine:    instance.Engine,
+		Provider:  instance.Provider,
+		Collected: collected.UTC(),
+	}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, ManifestFile{Name: f.Name, Size: len(f.Data), Error: f.Error})
+	}
+	m, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal manifest")
+	}
+	files = append([]DiagnosticsFile{{Name: "manifest.json", Data: m}}, files...)
+
+	return writeArchive(w, DiagnosticsDir(instance.Name, collected), collected, files)
+}
+
+func writeArchive(w io.Writer, dir string, modTime time.Time, files []DiagnosticsFile) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    dir + "/" + f.Name,
+			Mode:    0644,
+			Size:    int64(len(f.Data)),
+			ModTime: modTime,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "write %s header", f.Name)
+		}
+		_, err = tw.Write(f.Data)
+		if err != nil {
+			return errors.Wrapf(err, "write %s", f.Name)
+		}
+	}
+	err := tw.Close()
+	if err != nil {
+		return errors.Wrap(err, "close tar")
+	}
+
+	return errors.Wrap(gz.Close(), "close gzip")
+}
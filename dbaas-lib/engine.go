@@ -1,8 +1,13 @@
 package dbaas
 
+import (
+	"io"
+	"time"
+)
+
 type Engine interface {
 	ParseOptions(opts string) error
-	CreateDBCluster(name, opts, rootPass, version string) error
+	CreateDBCluster(instance Instance) error
 	DeleteDBCluster(name, opts, version string, delePVC bool) (string, error)
 	GetDBCluster(name, opts string) (DB, error)
 	GetDBClusterList() ([]DB, error)
@@ -10,6 +15,77 @@ type Engine interface {
 	PreCheck(name, opts, version string) ([]string, error)
 }
 
+// EncryptionKeyRotator is implemented by engines supporting data-at-rest encryption
+type EncryptionKeyRotator interface {
+	RotateEncryptionKey(name string) error
+}
+
+// Scaler is implemented by engines supporting horizontal scaling
+type Scaler interface {
+	ScaleDBCluster(name string, scale Scale) error
+}
+
+// StorageResizer is implemented by engines supporting volume expansion
+type StorageResizer interface {
+	ResizeStorage(name, size string, wait time.Duration) (string, error)
+}
+
+// Cloner is implemented by engines which can copy clusters
+type Cloner interface {
+	CloneDBCluster(source string, target Instance, timeout time.Duration) error
+}
+
+// BackupScheduler is implemented by engines supporting scheduled backups
+type BackupScheduler interface {
+	AddBackupSchedule(name string, schedule BackupSchedule, storage *BackupStorage) error
+	ListBackupSchedules(name string) ([]BackupSchedule, []BackupStorage, error)
+	RemoveBackupSchedule(name, schedule string) error
+}
+
+// Restorer is implemented by engines which can restore the cluster data from backups
+type Restorer interface {
+	SelectBackup(name string, toTime time.Time) (Backup, error)
+	RestoreDBCluster(name string, restore Restore) (string, error)
+}
+
+// Monitor is implemented by engines which can set up PMM monitoring of the cluster
+type Monitor interface {
+	EnableMonitoring(name string, m Monitoring) error
+	DisableMonitoring(name string, wait time.Duration) error
+}
+
+// HealthChecker is implemented by engines which can check the health of the cluster objects
+type HealthChecker interface {
+	CheckHealth(name string) ([]HealthCheck, error)
+}
+
+// DiagnosticsCollector is implemented by engines which can gather the cluster state for support cases
+type DiagnosticsCollector interface {
+	CollectDiagnostics(name string) ([]DiagnosticsFile, error)
+}
+
+// LogStreamer is implemented by engines which can stream the logs of the cluster components
+type LogStreamer interface {
+	StreamLogs(name string, logs Logs, w io.Writer) error
+}
+
+// EventsLister is implemented by engines which can list the events of the cluster objects
+type EventsLister interface {
+	ListEvents(name string) ([]Event, error)
+}
+
+// Labeler is implemented by engines which can change the labels of the existing clusters
+type Labeler interface {
+	LabelDBCluster(name string, set map[string]string, remove []string) error
+}
+
+// PreservedDataManager is implemented by engines which can keep the data of deleted clusters
+// and create clusters from it
+type PreservedDataManager interface {
+	ListPreservedData() ([]PreservedData, error)
+	PurgePreservedData(name string) error
+}
+
 var Providers = make(map[string]Provider)
 
 type Provider struct {
@@ -1,18 +1,42 @@
 package psmdb
 
-import "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+import (
+	"time"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
 
 type PSMDBCluster interface {
 	Upgrade(imgs map[string]string)
 	GetName() string
 	SetName(name string)
 	SetUsersSecretName(name string)
+	GetUsersSecretName() string
+	SetTLSSecretsName(secret, internalSecret string) error
+	GetTLSSecretName() string
+	SetEncryptionKeySecret(name string) error
+	GetEncryptionKeySecret() string
 	MarshalRequests() error
 	GetCR() (string, error)
 	SetLabels(labels map[string]string)
+	GetLabels() map[string]string
+	GetAnnotations() map[string]string
+	SetAnnotations(annotations map[string]string)
 	GetOperatorImage() string
 	SetDefaults() error
 	SetupMiniConfig()
 	GetStatus() dbaas.State
 	GetReplestsNames() []string
+	GetComponents() []dbaas.ComponentStatus
+	SetReplsetSize(name string, size int32) error
+	GetImage() string
+	GetStorageSize() string
+	IsPaused() bool
+	GetCreationTime() time.Time
+	GetBackupSchedules() []dbaas.BackupSchedule
+	SetBackupSchedules(schedules []dbaas.BackupSchedule) error
+	GetBackupStorages() []dbaas.BackupStorage
+	SetBackupStorage(storage dbaas.BackupStorage) error
+	GetPMM() (enabled bool, serverHost string)
+	SetPMM(enabled bool, serverHost string)
 }
@@ -5,35 +5,97 @@ import (
 	"encoding/json"
 	"math/big"
 	mrand "math/rand"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 )
 
 // CreateDBCluster start creating DB cluster
-func (p *PSMDB) CreateDBCluster(name, opts, rootPass, version string) error {
-	err := p.setVersionObjectsWithDefaults(Version(version))
+func (p *PSMDB) CreateDBCluster(instance dbaas.Instance) (err error) {
+	name := instance.Name
+	err = p.setVersionObjectsWithDefaults(Version(instance.Version))
 	if err != nil {
 		return errors.Wrap(err, "version check")
 	}
-	err = p.ParseOptions(opts)
+	// the sizing set explicitly by the preset and options is kept on minikube and minishift
+	switch p.platformType {
+	case k8s.PlatformMinishift, k8s.PlatformMinikube:
+		p.conf.SetupMiniConfig()
+	}
+	if len(instance.Preset) > 0 {
+		err = p.applyPreset(instance.Preset)
+		if err != nil {
+			return errors.Wrap(err, "apply preset")
+		}
+	}
+	err = p.ParseOptions(instance.EngineOptions)
 	if err != nil {
 		return errors.Wrap(err, "parse opts")
 	}
 	p.conf.SetName(name)
-	p.conf.SetUsersSecretName(name)
+	if len(instance.Preset) > 0 {
+		p.setAnnotation(dbaas.PresetAnnotation, instance.Preset)
+	}
+	for k, v := range instance.Annotations {
+		p.setAnnotation(k, v)
+	}
+	if len(instance.Labels) > 0 {
+		p.conf.SetLabels(instance.Labels)
+	}
 
-	switch p.platformType {
-	case k8s.PlatformMinishift, k8s.PlatformMinikube:
-		p.conf.SetupMiniConfig()
+	if len(instance.UsersSecret) > 0 {
+		if len(instance.RootPass) > 0 {
+			return errors.New("root password can't be set together with the users secret")
+		}
+		err = p.checkUsersSecret(instance.UsersSecret)
+		if err != nil {
+			return errors.Wrap(err, "check users secret")
+		}
+		p.conf.SetUsersSecretName(instance.UsersSecret)
+	} else {
+		p.conf.SetUsersSecretName(usersSecretName(name))
+	}
+
+	if len(instance.FromPreserved) > 0 {
+		version := instance.Version
+		if len(version) == 0 {
+			version = defaultVersion
+		}
+		encrypted, err := p.setupFromPreserved(instance, version)
+		if err != nil {
+			return errors.Wrap(err, "setup preserved data")
+		}
+		// mongod can't read the data without the key it was encrypted with
+		instance.Encryption = instance.Encryption || encrypted
 	}
 
-	if len(rootPass) > 0 {
-		err = p.SetupPasswords(name, rootPass)
+	if instance.Encryption {
+		err = p.setupEncryption(name)
+		if err != nil {
+			return errors.Wrap(err, "setup encryption")
+		}
+	}
+
+	tlsSecrets, err := p.setupTLS(instance)
+	if err != nil {
+		return errors.Wrap(err, "setup TLS")
+	}
+	// the certificates of the cluster that isn't created would be picked by the next one with the same name
+	defer func() {
+		if err == nil || len(tlsSecrets) == 0 {
+			return
+		}
+		if derr := p.cmd.DeleteSecrets(tlsSecrets); derr != nil {
+			err = errors.WithMessagef(err, "%v", derr)
+		}
+	}()
+
+	if len(instance.RootPass) > 0 {
+		err = p.SetupPasswords(name, instance.RootPass)
 		if err != nil {
 			return errors.Wrap(err, "set root password")
 		}
@@ -63,7 +125,7 @@ func (p *PSMDB) DeleteDBCluster(name, opts, version string, delePVC bool) (strin
 		return "", errors.Wrap(err, "check if cluster exists")
 	}
 	if !ext {
-		return "", errors.New("unable to find cluster psmdb/" + name)
+		return "", errors.Wrapf(dbaas.ErrNotFound, "cluster psmdb/%s", name)
 	}
 	cluster, err := p.cmd.GetObject("psmdb", name)
 	if err != nil {
@@ -76,37 +138,39 @@ func (p *PSMDB) DeleteDBCluster(name, opts, version string, delePVC bool) (strin
 	}
 	p.conf.SetDefaults()
 	p.conf.SetName(name)
+	st := p.conf
+	err = json.Unmarshal(cluster, st)
+	if err != nil {
+		return "", errors.Wrap(err, "unmarshal object")
+	}
 
+	var volumes []string
+	if !delePVC {
+		volumes, err = p.cmd.PreserveVolumes(p.operatorName(), name, "psmdb", cluster)
+		if err != nil {
+			return "", errors.Wrap(err, "preserve volumes")
+		}
+	}
 	err = p.cmd.DeleteCluster("psmdb", p.operatorName(), name, delePVC)
 	if err != nil {
 		return "", errors.Wrap(err, "delete cluster")
 	}
 	if !delePVC {
-		st := p.conf
-		err = json.Unmarshal(cluster, st)
-		if err != nil {
-			return "", errors.Wrap(err, "unmarshal object")
-		}
-
-		rsName := "rs0"
-		for _, name := range st.GetReplestsNames() {
-			rsName = name
-		}
-
-		pvcObj, err := p.cmd.GetObject("pvc", "mongod-data-"+name+"-"+rsName+"-0")
+		return strings.Join(volumes, ", "), nil
+	}
+	// a secret provided by the user is not owned by the cluster, so it outlives it
+	if st.GetUsersSecretName() == usersSecretName(name) {
+		err = p.cmd.DeleteObject("secret", usersSecretName(name))
 		if err != nil {
-			return "", errors.Wrap(err, "get pvc")
+			return "", errors.Wrap(err, "delete secret")
 		}
-		pvc := &corev1.PersistentVolumeClaim{}
-		err = json.Unmarshal(pvcObj, pvc)
+	}
+	// the key is kept along with the preserved data, otherwise it is useless
+	if st.GetEncryptionKeySecret() == encryptionKeySecretName(name) {
+		err = p.cmd.DeleteObject("secret", encryptionKeySecretName(name))
 		if err != nil {
-			return "", errors.Wrap(err, "unmarshal pvc")
+			return "", errors.Wrap(err, "delete encryption key secret")
 		}
-		return "pvc/" + pvc.Name, nil
-	}
-	err = p.cmd.DeleteObject("secret", name+"-psmdb-users-secrets")
-	if err != nil {
-		return "", errors.Wrap(err, "delete secret")
 	}
 
 	return "", nil
@@ -119,11 +183,6 @@ func (p *PSMDB) GetDBCluster(name, opts string) (dbaas.DB, error) {
 	if err != nil {
 		return db, errors.Wrap(err, "version check")
 	}
-	secrets, err := p.cmd.GetSecrets(name + "-psmdb-users-secrets")
-	if err != nil {
-		return db, errors.Wrap(err, "get cluster secrets")
-
-	}
 	cluster, err := p.cmd.GetObject("psmdb", name)
 	if err != nil {
 		return db, errors.Wrap(err, "get cluster object")
@@ -134,6 +193,11 @@ func (p *PSMDB) GetDBCluster(name, opts string) (dbaas.DB, error) {
 	if err != nil {
 		return db, errors.Wrap(err, "unmarshal object")
 	}
+	secrets, err := p.cmd.GetSecrets(st.GetUsersSecretName())
+	if err != nil {
+		return db, errors.Wrap(err, "get cluster secrets")
+
+	}
 	err = p.checkClusterPods(name)
 	if err != nil {
 		db.Status = "error"
@@ -158,8 +222,19 @@ func (p *PSMDB) GetDBCluster(name, opts string) (dbaas.DB, error) {
 	db.User = string(secrets["MONGODB_CLUSTER_ADMIN_USER"])
 	db.Pass = string(secrets["MONGODB_CLUSTER_ADMIN_PASSWORD"])
 	db.Status = st.GetStatus()
+	db.Preset = st.GetAnnotations()[dbaas.PresetAnnotation]
+	db.Monitoring = p.monitoringStatus(name)
+	p.setDetails(&db, cluster)
+	db.CAFingerprint, err = p.caFingerprint()
+	if err != nil && db.Status == dbaas.StateReady {
+		return db, errors.Wrap(err, "get CA fingerprint")
+	}
 	if st.GetStatus() == dbaas.StateReady {
 		db.Message = "To access database please run the following commands:\nkubectl port-forward svc/" + name + "-" + rsName + " 27017:27017 &\nmongo mongodb://" + db.User + ":PASSWORD@localhost:27017/admin?ssl=false"
+		if len(db.CAFingerprint) > 0 {
+			// the certificate is issued for the service name, so the hostname can't be verified through port-forward
+			db.Message = "To access database please run the following commands:\nkubectl get secret " + st.GetTLSSecretName() + " -o jsonpath='{.data.ca\\.crt}' | base64 --decode > ca.crt\nkubectl port-forward svc/" + name + "-" + rsName + " 27017:27017 &\nmongo 'mongodb://" + db.User + ":PASSWORD@localhost:27017/admin?tls=true&tlsCAFile=ca.crt&tlsAllowInvalidHostnames=true'"
+		}
 	}
 
 	return db, nil
@@ -169,9 +244,11 @@ func (p *PSMDB) GetDBCluster(name, opts string) (dbaas.DB, error) {
 func (p *PSMDB) GetDBClusterList() ([]dbaas.DB, error) {
 	var dbList []dbaas.DB
 	cluster, err := p.cmd.GetObjects("psmdb")
-	if err != nil {
+	if err == k8s.ErrNotFound {
+		// there are no clusters or the operator custom resource isn't installed
+		return dbList, nil
+	} else if err != nil {
 		return dbList, errors.Wrap(err, "get cluster object")
-
 	}
 
 	st := k8s.Clusters{}
@@ -183,13 +260,18 @@ func (p *PSMDB) GetDBClusterList() ([]dbaas.DB, error) {
 	if err != nil {
 		return dbList, errors.Wrap(err, "version check")
 	}
+	// every cluster is read into the fresh object, so the fields missing in its
+	// custom resource aren't taken from the previous one
+	conf := p.conf
+	defer func() { p.conf = conf }()
 	for _, c := range st.Items {
 		b, err := json.Marshal(c)
 		if err != nil {
 			return dbList, errors.Wrap(err, "marshal")
 		}
 
-		err = json.Unmarshal(b, &p.conf)
+		p.conf = reflect.New(reflect.TypeOf(conf).Elem()).Interface().(PSMDBCluster)
+		err = json.Unmarshal(b, p.conf)
 		if err != nil {
 			return dbList, errors.Wrap(err, "unmarshal psmdb object")
 		}
@@ -198,6 +280,7 @@ func (p *PSMDB) GetDBClusterList() ([]dbaas.DB, error) {
 			ResourceName: psmdb.GetName(),
 			Status:       psmdb.GetStatus(),
 		}
+		p.setDetails(&db, b)
 		dbList = append(dbList, db)
 	}
 
@@ -221,7 +304,6 @@ func (p *PSMDB) UpdateDBCluster(name, opts, version string) error {
 	}
 	p.ParseOptions(opts)
 	p.conf.SetName(name)
-	p.conf.SetUsersSecretName(name)
 
 	cr, err := p.getCR(p.conf)
 	if err != nil {
@@ -236,7 +318,7 @@ func (p *PSMDB) UpdateDBCluster(name, opts, version string) error {
 }
 
 func (p *PSMDB) SetupPasswords(clusterName, rootPass string) error {
-	secretName := clusterName + "-psmdb-users-secrets"
+	secretName := usersSecretName(clusterName)
 	ext, err := p.cmd.IsObjExists("secret", secretName)
 	if err != nil {
 		return errors.Wrap(err, "check if secrets exists")
@@ -286,6 +368,40 @@ func (p *PSMDB) SetupPasswords(clusterName, rootPass string) error {
 	return nil
 }
 
+// usersSecretKeys are the keys the operator expects to find in the users secret
+var usersSecretKeys = []string{
+	"MONGODB_BACKUP_USER",
+	"MONGODB_BACKUP_PASSWORD",
+	"MONGODB_CLUSTER_ADMIN_USER",
+	"MONGODB_CLUSTER_ADMIN_PASSWORD",
+	"MONGODB_CLUSTER_MONITOR_USER",
+	"MONGODB_CLUSTER_MONITOR_PASSWORD",
+	"MONGODB_USER_ADMIN_USER",
+	"MONGODB_USER_ADMIN_PASSWORD",
+}
+
+func usersSecretName(clusterName string) string {
+	return clusterName + "-psmdb-users-secrets"
+}
+
+func (p *PSMDB) checkUsersSecret(secretName string) error {
+	data, err := p.cmd.GetSecrets(secretName)
+	if err != nil {
+		return errors.Wrapf(err, "get secret %s", secretName)
+	}
+	var missing []string
+	for _, k := range usersSecretKeys {
+		if len(data[k]) == 0 {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("secret %s has no values for keys: %s", secretName, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
 const (
 	passwordMaxLen = 20
 	passwordMinLen = 16
@@ -332,16 +448,43 @@ func (p *PSMDB) checkClusterPods(name string) error {
 	if err != nil {
 		return errors.Wrap(err, "unmarshal pods data")
 	}
-	for _, pod := range pods.Items {
-		if pod.Status.Phase != "Pending" {
-			return nil
-		}
-		for _, condition := range pod.Status.Conditions {
-			if condition.Status == "False" && strings.Contains(condition.Message, "Insufficient memory") {
-				return k8s.ErrOutOfMemory
-			}
-		}
+
+	return k8s.PodsProblem(pods.Items)
+}
+
+// applyPreset sets the cluster components sizing from the preset
+func (p *PSMDB) applyPreset(name string) error {
+	preset, err := dbaas.GetPreset(name)
+	if err != nil {
+		return err
 	}
+	var opts []string
+	opts = append(opts, preset.Components["mongod"].Options("spec.replsets")...)
 
-	return nil
+	return p.ParseOptions(strings.Join(opts, ","))
+}
+
+func (p *PSMDB) setAnnotation(key, value string) {
+	annotations := p.conf.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	p.conf.SetAnnotations(annotations)
+}
+
+// setDetails sets the versions, the components status, the storage size, the age and the backup
+// schedules of the cluster from the loaded object and its custom resource
+func (p *PSMDB) setDetails(db *dbaas.DB, cr []byte) {
+	db.Provider = provider
+	db.Engine = engine
+	db.EngineVersion = k8s.ImageVersion(p.conf.GetImage(), "-mongod")
+	db.OperatorVersion, _ = k8s.CRVersion(cr)
+	db.Size = p.conf.GetStorageSize()
+	db.Components = p.conf.GetComponents()
+	db.Created = p.conf.GetCreationTime()
+	db.Paused = p.conf.IsPaused()
+	db.BackupSchedules = p.conf.GetBackupSchedules()
+	db.Labels = p.conf.GetLabels()
+	db.Annotations = dbaas.DBAnnotations(p.conf.GetAnnotations())
 }
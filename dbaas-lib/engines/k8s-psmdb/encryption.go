@@ -0,0 +1,200 @@
+package psmdb
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+const (
+	encryptionKeyKey = "encryption-key"
+	// resyncedKey is the key of the previous key secret with the members resynced with the new key
+	resyncedKey = "resynced-members"
+	// resyncTimeout is how long a member may take to come back after the data directory is wiped
+	resyncTimeout = 2 * time.Hour
+)
+
+func encryptionKeySecretName(clusterName string) string {
+	return clusterName + "-mongodb-encryption-key"
+}
+
+// setupEncryption enables data-at-rest encryption with the key from the secret derived from the cluster name.
+// The existing secret is reused, so the data preserved after the cluster deletion can be read again
+func (p *PSMDB) setupEncryption(name string) error {
+	secret := encryptionKeySecretName(name)
+	err := p.conf.SetEncryptionKeySecret(secret)
+	if err != nil {
+		return err
+	}
+
+	ext, err := p.cmd.IsObjExists("secret", secret)
+	if err != nil {
+		return errors.Wrap(err, "check if secret exists")
+	}
+	if ext {
+		return nil
+	}
+	key, err := generateEncryptionKey()
+	if err != nil {
+		return errors.Wrap(err, "generate key")
+	}
+
+	return errors.WithMessage(p.cmd.CreateSecret(secret, map[string][]byte{encryptionKeyKey: key}), "create secret")
+}
+
+// generateEncryptionKey returns base64 encoded 256-bit key as mongod expects it in the key file
+func generateEncryptionKey() ([]byte, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// RotateEncryptionKey replaces the data-at-rest encryption key of the cluster.
+// mongod reads the key only at startup and can't re-encrypt existing data files, so every member
+// is recreated with the empty data directory one by one and does the initial sync from the rest
+// of the replica set. The previous key and the resynced members are kept in the "<secret>-previous"
+// secret until all members are resynced. If the secret exists, the interrupted rotation is resumed:
+// the keys are kept and only the members that aren't resynced yet are recreated.
+// The members that aren't resynced yet read the new key if they restart for any other reason and
+// can't start with the data encrypted by the previous one, resuming the rotation resyncs them
+func (p *PSMDB) RotateEncryptionKey(name string) error {
+	err := p.setVersionObjectsWithDefaults(Version(""))
+	if err != nil {
+		return errors.Wrap(err, "version check")
+	}
+	cluster, err := p.cmd.GetObject("psmdb", name)
+	if err != nil {
+		return errors.Wrap(err, "get cluster object")
+	}
+	err = json.Unmarshal(cluster, p.conf)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal object")
+	}
+	secret := p.conf.GetEncryptionKeySecret()
+	if len(secret) == 0 {
+		return errors.Errorf("encryption is disabled for cluster %s", name)
+	}
+
+	prevSecret := secret + "-previous"
+	resume, err := p.cmd.IsObjExists("secret", prevSecret)
+	if err != nil {
+		return errors.Wrap(err, "check if secret exists")
+	}
+	// the members that failed to start with the new key are resynced when the rotation is resumed
+	if !resume && p.conf.GetStatus() != dbaas.StateReady {
+		return errors.Errorf("cluster %s should be ready to rotate the key", name)
+	}
+
+	members, err := p.replsetMembers(name)
+	if err != nil {
+		return errors.Wrap(err, "get replset members")
+	}
+
+	key, err := p.cmd.GetSecrets(secret)
+	if err != nil {
+		return errors.Wrap(err, "get current key")
+	}
+	prev := map[string][]byte{encryptionKeyKey: key[encryptionKeyKey]}
+	if resume {
+		prev, err = p.cmd.GetSecrets(prevSecret)
+		if err != nil {
+			return errors.Wrap(err, "get previous key")
+		}
+	} else {
+		err = p.cmd.CreateSecret(prevSecret, prev)
+		if err != nil {
+			return errors.Wrap(err, "save current key")
+		}
+	}
+
+	// the rotation may be interrupted before the new key is saved
+	if string(key[encryptionKeyKey]) == string(prev[encryptionKeyKey]) {
+		newKey, err := generateEncryptionKey()
+		if err != nil {
+			return errors.Wrap(err, "generate key")
+		}
+		err = p.cmd.UpdateSecrets(secret, map[string][]byte{encryptionKeyKey: newKey})
+		if err != nil {
+			return errors.Wrap(err, "update key")
+		}
+	}
+
+	resynced := make(map[string]bool)
+	for _, pod := range strings.Split(string(prev[resyncedKey]), ",") {
+		resynced[pod] = len(pod) > 0
+	}
+	for _, pod := range members {
+		if resynced[pod] {
+			continue
+		}
+		err = p.cmd.RecreatePodWithData(pod, "mongod-data-"+pod, resyncTimeout)
+		if err == nil {
+			err = p.waitMemberSynced(pod)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "resync %s, run the rotation again to resume it, the previous key is kept in secret %s", pod, prevSecret)
+		}
+		prev[resyncedKey] = []byte(strings.TrimPrefix(string(prev[resyncedKey])+","+pod, ","))
+		err = p.cmd.UpdateSecrets(prevSecret, prev)
+		if err != nil {
+			return errors.Wrap(err, "save resynced members")
+		}
+	}
+
+	return errors.WithMessage(p.cmd.DeleteObject("secret", prevSecret), "delete previous key")
+}
+
+// replsetMembers returns the cluster mongod pods in the order they should be resynced.
+// The members with the higher ordinals go first as pod 0 is the most likely primary
+func (p *PSMDB) replsetMembers(name string) ([]string, error) {
+	podsData, err := p.cmd.GetObjectByLables("pods", "app.kubernetes.io/instance="+name+",app.kubernetes.io/component=mongod")
+	if err != nil {
+		return nil, errors.Wrap(err, "get pods")
+	}
+	var pods k8s.Pods
+	err = json.Unmarshal(podsData, &pods)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal pods data")
+	}
+
+	rsSize := make(map[string]int)
+	var members []string
+	for _, pod := range pods.Items {
+		rsSize[pod.Labels["app.kubernetes.io/replset"]]++
+		members = append(members, pod.Name)
+	}
+	for rs, size := range rsSize {
+		if size < 2 {
+			return nil, errors.Errorf("replset %s has %d member, at least 2 are needed to resync the data", rs, size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(members)))
+
+	return members, nil
+}
+
+// waitMemberSynced waits until the member finishes the initial sync and becomes primary or secondary
+func (p *PSMDB) waitMemberSynced(pod string) error {
+	deadline := time.Now().Add(resyncTimeout)
+	for time.Now().Before(deadline) {
+		out, err := p.cmd.Exec(pod, "mongod", "mongo", "--quiet", "--eval", "db.isMaster().ismaster || db.isMaster().secondary")
+		if err == nil && strings.TrimSpace(string(out)) == "true" {
+			return nil
+		}
+		time.Sleep(5 * time.Second)
+	}
+
+	return errors.Errorf("member %s hasn't finished initial sync in %s", pod, resyncTimeout)
+}
@@ -0,0 +1,28 @@
+package psmdb
+
+import "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+
+// ListEvents returns the timeline of the events of the cluster custom resource, pods, volume claims and services
+func (p *PSMDB) ListEvents(name string) ([]dbaas.Event, error) {
+	events, err := p.cmd.ClusterEvents(name)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dbaas.Event, 0, len(events))
+	for _, e := range events {
+		list = append(list, dbaas.Event{
+			First:   e.First,
+			Last:    e.Last,
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Object:  e.Object,
+			Message: e.Message,
+			Count:   e.Count,
+			Problem: e.Problem,
+			Hint:    e.Hint(),
+		})
+	}
+
+	return list, nil
+}
@@ -0,0 +1,23 @@
+package psmdb
+
+import "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+
+// CheckHealth checks the cluster custom resource status, the pods, volumes and disruption budgets of the cluster
+func (p *PSMDB) CheckHealth(name string) ([]dbaas.HealthCheck, error) {
+	checks, err := p.cmd.CheckHealth("psmdb", name)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dbaas.HealthCheck, 0, len(checks))
+	for _, c := range checks {
+		list = append(list, dbaas.HealthCheck{
+			Component: c.Component,
+			Object:    c.Object,
+			Status:    c.Status,
+			Message:   c.Message,
+		})
+	}
+
+	return list, nil
+}
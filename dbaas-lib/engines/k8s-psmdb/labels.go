@@ -0,0 +1,24 @@
+package psmdb
+
+// LabelDBCluster sets and removes the labels of the cluster custom resource. The operator copies
+// them to the pods, which restarts the pods
+func (p *PSMDB) LabelDBCluster(name string, set map[string]string, remove []string) error {
+	err := p.loadCluster(name)
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[string]string)
+	for k, v := range p.conf.GetLabels() {
+		labels[k] = v
+	}
+	for _, k := range remove {
+		delete(labels, k)
+	}
+	for k, v := range set {
+		labels[k] = v
+	}
+	p.conf.SetLabels(labels)
+
+	return p.applyCluster(name)
+}
@@ -0,0 +1,35 @@
+package psmdb
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// StreamLogs writes the logs of the cluster pods of the component and the operator log lines mentioning the cluster to w
+func (p *PSMDB) StreamLogs(name string, logs dbaas.Logs, w io.Writer) error {
+	ext, err := p.cmd.IsObjExists("psmdb", name)
+	if err != nil {
+		return errors.Wrap(err, "check if cluster exists")
+	}
+	if !ext {
+		return errors.Wrapf(dbaas.ErrNotFound, "cluster psmdb/%s", name)
+	}
+
+	labels := "app.kubernetes.io/instance=" + name + ",app.kubernetes.io/component=mongod"
+	operator := p.operatorName()
+	switch logs.Component {
+	case "":
+	case "operator":
+		labels = ""
+	case "mongod":
+		operator = ""
+	default:
+		return errors.Errorf("unknown component %s, use mongod or operator", logs.Component)
+	}
+
+	return p.cmd.StreamLogs(w, labels, operator, name, k8s.LogsOptions{Follow: logs.Follow, Since: logs.Since})
+}
@@ -0,0 +1,107 @@
+package psmdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+const (
+	pmmContainer = "pmm-client"
+	// pmmUserKey and pmmPasswordKey are the keys of the PMM server credentials in the users secret the operator reads
+	pmmUserKey     = "PMM_SERVER_USER"
+	pmmPasswordKey = "PMM_SERVER_PASSWORD"
+	// pmmPodsLabels select the pods the operator adds the PMM client sidecar to
+	pmmPodsLabels = "app.kubernetes.io/component=mongod,app.kubernetes.io/instance="
+)
+
+// EnableMonitoring adds the PMM client sidecars to the cluster pods. The PMM server credentials are
+// stored in the cluster users secret, the password is copied from the given secret
+func (p *PSMDB) EnableMonitoring(name string, m dbaas.Monitoring) error {
+	err := p.loadCluster(name)
+	if err != nil {
+		return err
+	}
+	_, host := p.conf.GetPMM()
+	if len(m.ServerHost) > 0 {
+		host = m.ServerHost
+	}
+	if len(host) == 0 {
+		return errors.New("PMM server host is required")
+	}
+
+	usersSecret := p.conf.GetUsersSecretName()
+	secrets, err := p.cmd.GetSecrets(usersSecret)
+	if err != nil {
+		return errors.Wrap(err, "get cluster secrets")
+	}
+	if len(m.ServerUser) > 0 {
+		secrets[pmmUserKey] = []byte(m.ServerUser)
+	}
+	if len(m.PasswordSecret) > 0 {
+		secrets[pmmPasswordKey], err = p.pmmPassword(m.PasswordSecret)
+		if err != nil {
+			return err
+		}
+	}
+	if len(secrets[pmmUserKey]) == 0 || len(secrets[pmmPasswordKey]) == 0 {
+		return errors.New("PMM server user and password secret are required")
+	}
+	err = p.cmd.UpdateSecrets(usersSecret, secrets)
+	if err != nil {
+		return errors.Wrap(err, "update cluster secrets")
+	}
+
+	p.conf.SetPMM(true, host)
+	err = p.applyCluster(name)
+	if err != nil || m.Wait == 0 {
+		return err
+	}
+
+	return p.cmd.WaitContainers(pmmPodsLabels+name, pmmContainer, true, m.Wait)
+}
+
+// DisableMonitoring removes the PMM client sidecars from the cluster pods
+func (p *PSMDB) DisableMonitoring(name string, wait time.Duration) error {
+	err := p.loadCluster(name)
+	if err != nil {
+		return err
+	}
+	p.conf.SetPMM(false, "")
+	err = p.applyCluster(name)
+	if err != nil || wait == 0 {
+		return err
+	}
+
+	return p.cmd.WaitContainers(pmmPodsLabels+name, pmmContainer, false, wait)
+}
+
+func (p *PSMDB) pmmPassword(secret string) ([]byte, error) {
+	data, err := p.cmd.GetSecrets(secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get secret %s", secret)
+	}
+	pass := data["password"]
+	if len(pass) == 0 {
+		return nil, errors.Errorf("secret %s has no password key", secret)
+	}
+
+	return pass, nil
+}
+
+// monitoringStatus describes the PMM client of the cluster for describe-db
+func (p *PSMDB) monitoringStatus(name string) string {
+	enabled, host := p.conf.GetPMM()
+	if !enabled {
+		return "disabled"
+	}
+	ready, total, err := p.cmd.ContainersReady(pmmPodsLabels+name, pmmContainer)
+	if err != nil {
+		return "enabled, PMM server " + host
+	}
+
+	return fmt.Sprintf("enabled, PMM server %s, %d/%d clients ready", host, ready, total)
+}
@@ -0,0 +1,98 @@
+package psmdb
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// ListPreservedData returns the data left by the deleted PSMDB clusters
+func (p *PSMDB) ListPreservedData() ([]dbaas.PreservedData, error) {
+	list, err := p.cmd.ListPreservedData("psmdb", p.operatorName())
+	if err != nil {
+		return nil, errors.Wrap(err, "list preserved data")
+	}
+
+	data := make([]dbaas.PreservedData, 0, len(list))
+	for _, d := range list {
+		data = append(data, dbaas.PreservedData{
+			Cluster:     d.Cluster,
+			Engine:      d.Engine,
+			Version:     d.Version,
+			Volumes:     d.Volumes,
+			Size:        d.Size.String(),
+			Created:     d.Created,
+			PreservedAt: d.PreservedAt,
+		})
+	}
+
+	return data, nil
+}
+
+// PurgePreservedData deletes the volumes of the deleted cluster and the users and encryption key secrets kept along with them
+func (p *PSMDB) PurgePreservedData(name string) error {
+	err := p.cmd.PurgePreservedData("psmdb", p.operatorName(), name)
+	if err != nil {
+		return err
+	}
+	for _, secret := range []string{usersSecretName(name), encryptionKeySecretName(name)} {
+		ext, err := p.cmd.IsObjExists("secret", secret)
+		if err != nil {
+			return errors.Wrap(err, "check if secret exists")
+		}
+		if !ext {
+			continue
+		}
+		err = p.cmd.DeleteObject("secret", secret)
+		if err != nil {
+			return errors.Wrapf(err, "delete secret %s", secret)
+		}
+	}
+
+	return nil
+}
+
+// setupFromPreserved checks that the data of the deleted cluster can be used by the new one of
+// the version and moves it to the new cluster if the name differs. The users passwords are stored
+// in the data and the data can be encrypted, so the users and encryption key secrets of the deleted
+// cluster are moved as well. It returns true if the data is encrypted
+func (p *PSMDB) setupFromPreserved(instance dbaas.Instance, version string) (bool, error) {
+	if len(instance.RootPass) > 0 {
+		return false, errors.New("root password can't be set for the cluster created from preserved data")
+	}
+	d, err := p.cmd.GetPreservedData("psmdb", instance.FromPreserved)
+	if err == k8s.ErrNotFound {
+		return false, errors.Wrapf(dbaas.ErrNotFound, "preserved data of %s", instance.FromPreserved)
+	} else if err != nil {
+		return false, errors.Wrap(err, "get preserved data")
+	}
+	err = d.Check(p.operatorName(), version)
+	if err != nil {
+		return false, err
+	}
+
+	if instance.FromPreserved != instance.Name {
+		if len(instance.UsersSecret) == 0 {
+			err = p.cmd.MoveSecret(usersSecretName(instance.FromPreserved), usersSecretName(instance.Name))
+			if err != nil {
+				return false, errors.Wrap(err, "move users secret")
+			}
+		}
+		err = p.cmd.MoveSecret(encryptionKeySecretName(instance.FromPreserved), encryptionKeySecretName(instance.Name))
+		if err != nil {
+			return false, errors.Wrap(err, "move encryption key secret")
+		}
+		err = p.cmd.RebindPreservedData(d, instance.Name)
+		if err != nil {
+			return false, errors.Wrap(err, "move volumes")
+		}
+	}
+
+	encrypted, err := p.cmd.IsObjExists("secret", encryptionKeySecretName(instance.Name))
+	if err != nil {
+		return false, errors.Wrap(err, "check if encryption key secret exists")
+	}
+
+	return encrypted, nil
+}
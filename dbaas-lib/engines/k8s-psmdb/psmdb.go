@@ -3,6 +3,7 @@ package psmdb
 import (
 	"fmt"
 	"os"
+	"reflect"
 
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 	v110 "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb/types/v110"
@@ -90,20 +91,19 @@ func NewPSMDBController(envCrt, provider string) (*PSMDB, error) {
 }
 
 func (p *PSMDB) setVersionObjectsWithDefaults(version Version) error {
-	if p.conf != nil && p.bundle != nil {
-		return nil
-	}
 	if len(version) == 0 {
 		version = defaultVersion
 	}
 	if _, ok := objects[version]; !ok {
-		return errors.Errorf("unsupporeted version %s", version)
+		return errors.Wrapf(dbaas.ErrUnsupportedVersion, "operator version %s", version)
 	}
 
-	p.conf = objects[version].psmdb
+	// the engine is shared by the calls, so every one starts with the fresh object of the version
+	// rather than the one the previous call has read the other cluster into
+	p.conf = reflect.New(reflect.TypeOf(objects[version].psmdb).Elem()).Interface().(PSMDBCluster)
 	err := p.conf.SetDefaults()
 	if err != nil {
-		errors.Wrap(err, "set defaults")
+		return errors.Wrap(err, "set defaults")
 	}
 	p.bundle = objects[version].k8s.Bundle
 
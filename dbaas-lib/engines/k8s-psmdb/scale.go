@@ -0,0 +1,101 @@
+package psmdb
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// ScaleDBCluster changes the number of the members of the replset given in scale.Replset or of all
+// replsets. It checks that the nodes can fit the new pods of each replset and waits until they are
+// ready if scale.Wait is set
+func (p *PSMDB) ScaleDBCluster(name string, scale dbaas.Scale) error {
+	if scale.Replicas < 1 {
+		return errors.New("number of replicas should be positive")
+	}
+	if scale.Proxies > 0 {
+		return errors.New("psmdb clusters have no proxies")
+	}
+	if scale.Replicas%2 == 0 && !scale.Force {
+		return errors.Errorf("replset of %d members can't keep the quorum in case of the network split, use an odd number of members or force it", scale.Replicas)
+	}
+
+	err := p.setVersionObjectsWithDefaults(Version(""))
+	if err != nil {
+		return errors.Wrap(err, "version check")
+	}
+	cluster, err := p.cmd.GetObject("psmdb", name)
+	if err != nil {
+		return errors.Wrap(err, "get cluster object")
+	}
+	err = json.Unmarshal(cluster, p.conf)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal object")
+	}
+
+	var replsets []string
+	for _, rs := range p.conf.GetComponents() {
+		if len(scale.Replset) > 0 && rs.Name != scale.Replset {
+			continue
+		}
+		err = p.cmd.CheckCapacity(int(scale.Replicas-rs.Size), rs.Requests.CPU, rs.Requests.Memory)
+		if err != nil {
+			return errors.Wrapf(err, "check capacity for replset %s", rs.Name)
+		}
+		err = p.conf.SetReplsetSize(rs.Name, scale.Replicas)
+		if err != nil {
+			return err
+		}
+		replsets = append(replsets, rs.Name)
+	}
+	if len(replsets) == 0 {
+		return errors.Wrapf(dbaas.ErrNotFound, "replset %s of cluster psmdb/%s", scale.Replset, name)
+	}
+
+	cr, err := p.getCR(p.conf)
+	if err != nil {
+		return errors.Wrap(err, "get cr")
+	}
+	err = p.cmd.Upgrade("psmdb", name, cr)
+	if err != nil {
+		return errors.Wrap(err, "upgrade cluster")
+	}
+	if scale.Wait == 0 {
+		return nil
+	}
+
+	return p.waitScaled(name, replsets, scale.Replicas, scale.Wait)
+}
+
+// waitScaled waits until the status of the replsets reports the wanted number of ready members
+func (p *PSMDB) waitScaled(name string, replsets []string, replicas int32, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(5 * time.Second)
+
+		cluster, err := p.cmd.GetObject("psmdb", name)
+		if err != nil {
+			continue
+		}
+		err = json.Unmarshal(cluster, p.conf)
+		if err != nil {
+			return errors.Wrap(err, "unmarshal object")
+		}
+		ready := make(map[string]bool)
+		for _, c := range p.conf.GetComponents() {
+			ready[c.Name] = c.Ready == replicas
+		}
+		scaled := true
+		for _, rs := range replsets {
+			scaled = scaled && ready[rs]
+		}
+		if scaled {
+			return nil
+		}
+	}
+
+	return errors.Errorf("replset members aren't ready after %s", timeout)
+}
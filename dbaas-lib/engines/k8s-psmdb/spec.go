@@ -0,0 +1,49 @@
+package psmdb
+
+import (
+	"strings"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Options are the typed options of the psmdb engine for dbaas.CreateDBWithSpec and dbaas.ModifyDBWithSpec.
+// The zero fields keep the defaults on create and the current values on modify
+type Options struct {
+	// Replset is the options of the members of the replica set. The cluster has the single replset rs0
+	Replset Replset
+}
+
+// Replset are the options of the replica set members
+type Replset struct {
+	// Size is the number of the members
+	Size      int32
+	Resources dbaas.ResourceRequirements
+	// Storage is the volume size of each member, e.g. "3G"
+	Storage      string
+	StorageClass string
+}
+
+// Engine returns the name of the psmdb engine
+func (o Options) Engine() string {
+	return engine
+}
+
+// EngineOptions returns the options in the "spec.replsets.size=3,..." format
+func (o Options) EngineOptions() (string, error) {
+	c := dbaas.Component{
+		Size:     o.Replset.Size,
+		Requests: o.Replset.Resources.Requests,
+		Limits:   o.Replset.Resources.Limits,
+		Storage:  o.Replset.Storage,
+	}
+	err := c.Validate("spec.replsets")
+	if err != nil {
+		return "", err
+	}
+	opts := c.Options("spec.replsets")
+	if len(o.Replset.StorageClass) > 0 {
+		opts = append(opts, "spec.replsets.volumespec.persistentvolumeclaim.storageclassname="+o.Replset.StorageClass)
+	}
+
+	return strings.Join(opts, ","), nil
+}
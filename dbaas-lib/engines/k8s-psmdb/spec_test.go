@@ -0,0 +1,58 @@
+package psmdb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	v140 "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-psmdb/types/v140"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/options"
+	psmdbv1 "github.com/percona/percona-server-mongodb-operator/v140/pkg/apis/psmdb/v1"
+)
+
+func TestOptions(t *testing.T) {
+	o := Options{Replset: Replset{
+		Size:         5,
+		Resources:    dbaas.ResourceRequirements{Limits: dbaas.Resources{Memory: "4G"}},
+		Storage:      "20G",
+		StorageClass: "fast",
+	}}
+	opts, err := o.EngineOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the options are the ones the engine parses
+	cr := &v140.PerconaServerMongoDB{}
+	cr.Spec.Replsets = []*psmdbv1.ReplsetSpec{{Name: "rs0"}}
+	err = options.Parse(cr, reflect.TypeOf(cr), opts)
+	if err != nil {
+		t.Fatalf("parse %q: %v", opts, err)
+	}
+	rs := cr.Spec.Replsets[0]
+	if rs.Size != 5 || rs.Resources.Limits.Memory != "4G" || *rs.VolumeSpec.PersistentVolumeClaim.StorageClassName != "fast" {
+		t.Errorf("unexpected replset spec %+v from %q", rs, opts)
+	}
+
+	_, err = Options{Replset: Replset{Resources: dbaas.ResourceRequirements{Requests: dbaas.Resources{CPU: "half"}}}}.EngineOptions()
+	if err == nil {
+		t.Error("expected the error for the invalid cpu")
+	}
+}
+
+// The options are passed to dbaas.CreateDBWithSpec or dbaas.ModifyDBWithSpec,
+// which give the engine the same options in the text format
+func ExampleOptions() {
+	opts, err := Options{
+		Replset: Replset{
+			Size:    3,
+			Storage: "50G",
+		},
+	}.EngineOptions()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(opts)
+	// Output: spec.replsets.size=3,spec.replsets.volumespec.persistentvolumeclaim.resources.requests=storage:50G
+}
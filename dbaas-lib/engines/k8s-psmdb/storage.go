@@ -0,0 +1,65 @@
+package psmdb
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// ResizeStorage expands the volumes of the replset members. The operator doesn't resize volumes, so
+// the claims are patched directly and then the size is set in the CR for the new members.
+// The returned message lists the volumes which file systems are resized only when the pods are restarted
+func (p *PSMDB) ResizeStorage(name, size string, wait time.Duration) (string, error) {
+	err := p.setVersionObjectsWithDefaults(Version(""))
+	if err != nil {
+		return "", errors.Wrap(err, "version check")
+	}
+	cluster, err := p.cmd.GetObject("psmdb", name)
+	if err != nil {
+		return "", errors.Wrap(err, "get cluster object")
+	}
+	err = json.Unmarshal(cluster, p.conf)
+	if err != nil {
+		return "", errors.Wrap(err, "unmarshal object")
+	}
+	components := p.conf.GetComponents()
+	if len(components) != 1 {
+		return "", errors.Errorf("storage resize is supported for clusters with one replset, the cluster has %d", len(components))
+	}
+
+	restart, err := p.cmd.ExpandPVCs("app.kubernetes.io/instance="+name+",app.kubernetes.io/component=mongod", size, wait)
+	if err != nil {
+		return "", errors.Wrap(err, "expand volumes")
+	}
+	msg := ""
+	if len(restart) > 0 {
+		msg = "The file systems of the volumes " + strings.Join(restart, ", ") + " are resized when their pods are restarted"
+	}
+
+	err = p.ParseOptions(strings.Join(dbaas.Component{Storage: size}.Options("spec.replsets"), ","))
+	if err != nil {
+		return "", errors.Wrap(err, "parse options")
+	}
+	cr, err := p.getCR(p.conf)
+	if err != nil {
+		return "", errors.Wrap(err, "get cr")
+	}
+	err = p.cmd.Upgrade("psmdb", name, cr)
+	if err != nil {
+		return msg, errors.Wrap(err, "upgrade cluster, the volumes are expanded already, run the resize again to finish it")
+	}
+	// volume claim templates of the statefulset can't be changed, so the operator recreates it
+	// from the updated CR while the running pods are kept. The CR is updated first, otherwise
+	// the operator could recreate the statefulset with the old size
+	sts := name + "-" + components[0].Name
+	err = p.cmd.DeleteOrphan("statefulset", sts)
+	if err != nil {
+		return msg, errors.Wrapf(err, "delete statefulset %s, the volumes are expanded, but the new members get the old size until it is deleted with --cascade=false", sts)
+	}
+
+	return msg, nil
+}
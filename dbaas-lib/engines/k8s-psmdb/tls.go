@@ -0,0 +1,67 @@
+package psmdb
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// setupTLS configures certificates for client and intra-cluster connections according to the instance TLS mode.
+// It returns the names of the secrets it created
+func (p *PSMDB) setupTLS(instance dbaas.Instance) ([]string, error) {
+	name := instance.Name
+	switch instance.TLS {
+	case "", dbaas.TLSOff:
+		return nil, nil
+	case dbaas.TLSCustom:
+		if len(instance.TLSSecret) == 0 {
+			return nil, errors.New("secret with certificates is required for custom TLS")
+		}
+		internal := instance.TLSInternalSecret
+		if len(internal) == 0 {
+			internal = instance.TLSSecret
+		}
+		err := p.conf.SetTLSSecretsName(instance.TLSSecret, internal)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range []string{instance.TLSSecret, internal} {
+			err = p.cmd.CheckTLSSecret(s)
+			if err != nil {
+				return nil, errors.Wrap(err, "check TLS secret")
+			}
+		}
+		return nil, nil
+	case dbaas.TLSAuto:
+		secret, internal := name+"-ssl", name+"-ssl-internal"
+		err := p.conf.SetTLSSecretsName(secret, internal)
+		if err != nil {
+			return nil, err
+		}
+		var hosts []string
+		for _, rs := range p.conf.GetReplestsNames() {
+			hosts = append(hosts, name+"-"+rs, "*."+name+"-"+rs)
+		}
+		return p.cmd.SetupTLSSecrets(name, map[string][]string{
+			secret:   hosts,
+			internal: hosts,
+		})
+	}
+
+	return nil, errors.Errorf("unknown TLS mode %s", instance.TLS)
+}
+
+// caFingerprint returns fingerprint of the CA clients should trust or an empty string if TLS isn't configured
+func (p *PSMDB) caFingerprint() (string, error) {
+	secret := p.conf.GetTLSSecretName()
+	if len(secret) == 0 {
+		return "", nil
+	}
+	data, err := p.cmd.GetSecrets(secret)
+	if err != nil {
+		return "", errors.Wrapf(err, "get secret %s", secret)
+	}
+
+	return k8s.CAFingerprint(data[k8s.TLSCAKey])
+}
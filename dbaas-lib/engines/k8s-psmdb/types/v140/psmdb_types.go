@@ -2,6 +2,8 @@ package v140
 
 import (
 	"encoding/json"
+	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -21,6 +23,8 @@ type PerconaServerMongoDB struct {
 	Status v140.PerconaServerMongoDBStatus `json:"status,omitempty"`
 }
 
+const pmmImage = "percona/percona-server-mongodb-operator:1.4.0-pmm"
+
 func (cr *PerconaServerMongoDB) GetSpec() interface{} {
 	rs := v140.ReplsetSpec{}
 	cr.Spec.Replsets = []*v140.ReplsetSpec{&rs}
@@ -36,19 +40,81 @@ func (cr *PerconaServerMongoDB) SetName(name string) {
 }
 
 func (cr *PerconaServerMongoDB) SetUsersSecretName(name string) {
-	cr.Spec.Secrets = &v140.SecretsSpec{
-		Users: name + "-psmdb-users-secrets",
+	if cr.Spec.Secrets == nil {
+		cr.Spec.Secrets = &v140.SecretsSpec{}
+	}
+	cr.Spec.Secrets.Users = name
+}
+
+func (cr *PerconaServerMongoDB) GetUsersSecretName() string {
+	if cr.Spec.Secrets == nil {
+		return ""
+	}
+
+	return cr.Spec.Secrets.Users
+}
+
+func (cr *PerconaServerMongoDB) SetTLSSecretsName(secret, internalSecret string) error {
+	if cr.Spec.Secrets == nil {
+		cr.Spec.Secrets = &v140.SecretsSpec{}
+	}
+	cr.Spec.Secrets.SSL = secret
+	cr.Spec.Secrets.SSLInternal = internalSecret
+	return nil
+}
+
+func (cr *PerconaServerMongoDB) GetTLSSecretName() string {
+	if cr.Spec.Secrets == nil {
+		return ""
+	}
+	return cr.Spec.Secrets.SSL
+}
+
+func (cr *PerconaServerMongoDB) SetEncryptionKeySecret(name string) error {
+	t := true
+	if cr.Spec.Mongod == nil {
+		cr.Spec.Mongod = &v140.MongodSpec{}
+	}
+	if cr.Spec.Mongod.Security == nil {
+		cr.Spec.Mongod.Security = &v140.MongodSpecSecurity{}
+	}
+	cr.Spec.Mongod.Security.EnableEncryption = &t
+	cr.Spec.Mongod.Security.EncryptionKeySecret = name
+	return nil
+}
+
+// GetEncryptionKeySecret returns the name of secret with encryption key or empty string if the encryption is disabled
+func (cr *PerconaServerMongoDB) GetEncryptionKeySecret() string {
+	if cr.Spec.Mongod == nil || cr.Spec.Mongod.Security == nil ||
+		cr.Spec.Mongod.Security.EnableEncryption == nil || !*cr.Spec.Mongod.Security.EnableEncryption {
+		return ""
+	}
+	if len(cr.Spec.Mongod.Security.EncryptionKeySecret) == 0 {
+		// the operator default
+		return cr.ObjectMeta.Name + "-mongodb-encryption-key"
 	}
+
+	return cr.Spec.Mongod.Security.EncryptionKeySecret
 }
 
 func (cr *PerconaServerMongoDB) GetOperatorImage() string {
 	return "percona/percona-server-mongodb-operator:1.4.0"
 }
 
+// SetLabels sets the labels of the custom resource and of the replset pods
 func (cr *PerconaServerMongoDB) SetLabels(labels map[string]string) {
+	for _, rs := range cr.Spec.Replsets {
+		if rs != nil {
+			rs.Labels = dbaas.ReplaceLabels(rs.Labels, cr.ObjectMeta.Labels, labels)
+		}
+	}
 	cr.ObjectMeta.Labels = labels
 }
 
+func (cr *PerconaServerMongoDB) GetLabels() map[string]string {
+	return cr.ObjectMeta.Labels
+}
+
 func (cr *PerconaServerMongoDB) MarshalRequests() error {
 	if len(cr.Spec.Replsets) == 0 {
 		return errors.New("no replsets")
@@ -97,6 +163,65 @@ func (cr *PerconaServerMongoDB) GetReplestsNames() []string {
 	return replsetsNames
 }
 
+// GetComponents returns the size, readiness and resource requests of the cluster replsets
+// GetImage returns the image of the mongod members
+func (cr *PerconaServerMongoDB) GetImage() string {
+	return cr.Spec.Image
+}
+
+// GetStorageSize returns the size of the data volumes of the first replset
+func (cr *PerconaServerMongoDB) GetStorageSize() string {
+	if len(cr.Spec.Replsets) == 0 {
+		return ""
+	}
+	rs := cr.Spec.Replsets[0]
+	if rs.VolumeSpec == nil || rs.VolumeSpec.PersistentVolumeClaim == nil {
+		return ""
+	}
+	size, ok := rs.VolumeSpec.PersistentVolumeClaim.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return ""
+	}
+
+	return size.String()
+}
+
+func (cr *PerconaServerMongoDB) IsPaused() bool {
+	return cr.Spec.Pause
+}
+
+func (cr *PerconaServerMongoDB) GetCreationTime() time.Time {
+	return cr.ObjectMeta.CreationTimestamp.Time
+}
+
+func (cr *PerconaServerMongoDB) GetComponents() []dbaas.ComponentStatus {
+	var cs []dbaas.ComponentStatus
+	for _, rs := range cr.Spec.Replsets {
+		c := dbaas.ComponentStatus{Name: rs.Name, Size: rs.Size}
+		if st, ok := cr.Status.Replsets[rs.Name]; ok && st != nil {
+			c.Ready = st.Ready
+		}
+		if rs.Resources != nil && rs.Resources.Requests != nil {
+			c.Requests = dbaas.Resources{CPU: rs.Resources.Requests.CPU, Memory: rs.Resources.Requests.Memory}
+		}
+		cs = append(cs, c)
+	}
+
+	return cs
+}
+
+// SetReplsetSize sets the number of the members of the replset with the given name
+func (cr *PerconaServerMongoDB) SetReplsetSize(name string, size int32) error {
+	for _, rs := range cr.Spec.Replsets {
+		if rs != nil && rs.Name == name {
+			rs.Size = size
+			return nil
+		}
+	}
+
+	return errors.Errorf("no replset %s", name)
+}
+
 func (cr *PerconaServerMongoDB) SetDefaults() error {
 	rsName := "rs0"
 	rs := &v140.ReplsetSpec{
@@ -149,7 +274,101 @@ func (cr *PerconaServerMongoDB) SetDefaults() error {
 	cr.Spec.Mongod = &mongod
 	cr.Spec.PMM.Enabled = false
 	cr.Spec.PMM.ServerHost = "monitoring-service"
-	cr.Spec.PMM.Image = "percona/percona-server-mongodb-operator:1.4.0-pmm"
+	cr.Spec.PMM.Image = pmmImage
+
+	return nil
+}
+
+func (cr *PerconaServerMongoDB) GetBackupSchedules() []dbaas.BackupSchedule {
+	list := make([]dbaas.BackupSchedule, 0, len(cr.Spec.Backup.Tasks))
+	for _, t := range cr.Spec.Backup.Tasks {
+		list = append(list, dbaas.BackupSchedule{
+			Name:     t.Name,
+			Schedule: t.Schedule,
+			Storage:  t.StorageName,
+			Disabled: !t.Enabled,
+		})
+	}
+
+	return list
+}
+
+func (cr *PerconaServerMongoDB) SetBackupSchedules(schedules []dbaas.BackupSchedule) error {
+	tasks := make([]v140.BackupTaskSpec, 0, len(schedules))
+	for _, s := range schedules {
+		if s.Keep > 0 {
+			return errors.New("keeping the given number of backups isn't supported by the operator")
+		}
+		tasks = append(tasks, v140.BackupTaskSpec{
+			Name:        s.Name,
+			Enabled:     !s.Disabled,
+			Schedule:    s.Schedule,
+			StorageName: s.Storage,
+		})
+	}
+	cr.Spec.Backup.Tasks = tasks
+	if len(tasks) > 0 {
+		cr.Spec.Backup.Enabled = true
+	}
+
+	return nil
+}
+
+func (cr *PerconaServerMongoDB) GetBackupStorages() []dbaas.BackupStorage {
+	names := make([]string, 0, len(cr.Spec.Backup.Storages))
+	for name := range cr.Spec.Backup.Storages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]dbaas.BackupStorage, 0, len(names))
+	for _, name := range names {
+		s := cr.Spec.Backup.Storages[name]
+		list = append(list, dbaas.BackupStorage{
+			Name:              name,
+			Type:              string(s.Type),
+			Bucket:            s.S3.Bucket,
+			Region:            s.S3.Region,
+			EndpointURL:       s.S3.EndpointURL,
+			CredentialsSecret: s.S3.CredentialsSecret,
+		})
+	}
+
+	return list
+}
+
+func (cr *PerconaServerMongoDB) SetBackupStorage(storage dbaas.BackupStorage) error {
+	if storage.Type != "s3" {
+		return errors.Errorf("%s backup storage isn't supported by the operator, only s3 is", storage.Type)
+	}
+	if cr.Spec.Backup.Storages == nil {
+		cr.Spec.Backup.Storages = make(map[string]v140.BackupStorageSpec)
+	}
+	cr.Spec.Backup.Storages[storage.Name] = v140.BackupStorageSpec{
+		Type: v140.BackupStorageType(storage.Type),
+		S3: v140.BackupStorageS3Spec{
+			Bucket:            storage.Bucket,
+			Region:            storage.Region,
+			EndpointURL:       storage.EndpointURL,
+			CredentialsSecret: storage.CredentialsSecret,
+		},
+	}
+	cr.Spec.Backup.Enabled = true
 
 	return nil
 }
+
+func (cr *PerconaServerMongoDB) GetPMM() (enabled bool, serverHost string) {
+	return cr.Spec.PMM.Enabled, cr.Spec.PMM.ServerHost
+}
+
+// SetPMM turns the PMM client on or off, empty serverHost keeps the current value
+func (cr *PerconaServerMongoDB) SetPMM(enabled bool, serverHost string) {
+	if len(cr.Spec.PMM.Image) == 0 {
+		cr.Spec.PMM.Image = pmmImage
+	}
+	cr.Spec.PMM.Enabled = enabled
+	if len(serverHost) > 0 {
+		cr.Spec.PMM.ServerHost = serverHost
+	}
+}
@@ -0,0 +1,126 @@
+package pxc
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// AddBackupSchedule adds the scheduled backup to the cluster. The storage is added as well if it is given,
+// the S3 credentials secret is created from the keys if there is no secret. Otherwise the cluster should
+// have the storage already
+func (p *PXC) AddBackupSchedule(name string, schedule dbaas.BackupSchedule, storage *dbaas.BackupStorage) error {
+	err := p.loadCluster(name)
+	if err != nil {
+		return err
+	}
+
+	if storage != nil {
+		storage.Name = schedule.Storage
+		switch k8s.BackupStorageType(storage.Type) {
+		case k8s.BackupStorageS3:
+			spec, err := p.cmd.S3Storage(name, k8s.S3StorageConfig{
+				EndpointURL:       storage.EndpointURL,
+				Bucket:            storage.Bucket,
+				Region:            storage.Region,
+				CredentialsSecret: storage.CredentialsSecret,
+				KeyID:             storage.KeyID,
+				Key:               storage.Key,
+			})
+			if err != nil {
+				return errors.Wrap(err, "configure s3 storage")
+			}
+			storage.CredentialsSecret = spec.S3.CredentialsSecret
+		case k8s.BackupStorageFilesystem:
+			if len(storage.Size) == 0 {
+				return errors.New("volume size is required for filesystem storage")
+			}
+		default:
+			return errors.Errorf("unknown backup storage type %s", storage.Type)
+		}
+		err = p.conf.SetBackupStorage(*storage)
+		if err != nil {
+			return errors.Wrap(err, "set storage")
+		}
+	}
+
+	found := false
+	for _, s := range p.conf.GetBackupStorages() {
+		if s.Name == schedule.Storage {
+			found = true
+		}
+	}
+	if !found {
+		return errors.Errorf("cluster has no backup storage %s", schedule.Storage)
+	}
+
+	schedules := p.conf.GetBackupSchedules()
+	for _, s := range schedules {
+		if s.Name == schedule.Name {
+			return errors.Wrapf(dbaas.ErrAlreadyExists, "backup schedule %s", schedule.Name)
+		}
+	}
+	err = p.conf.SetBackupSchedules(append(schedules, schedule))
+	if err != nil {
+		return errors.Wrap(err, "set schedules")
+	}
+
+	return p.applyCluster(name)
+}
+
+// ListBackupSchedules returns the scheduled backups and the backup storages of the cluster
+func (p *PXC) ListBackupSchedules(name string) ([]dbaas.BackupSchedule, []dbaas.BackupStorage, error) {
+	err := p.loadCluster(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p.conf.GetBackupSchedules(), p.conf.GetBackupStorages(), nil
+}
+
+// RemoveBackupSchedule removes the scheduled backup from the cluster. The storage is kept
+func (p *PXC) RemoveBackupSchedule(name, schedule string) error {
+	err := p.loadCluster(name)
+	if err != nil {
+		return err
+	}
+
+	schedules := p.conf.GetBackupSchedules()
+	for i, s := range schedules {
+		if s.Name != schedule {
+			continue
+		}
+		err = p.conf.SetBackupSchedules(append(schedules[:i], schedules[i+1:]...))
+		if err != nil {
+			return errors.Wrap(err, "set schedules")
+		}
+		return p.applyCluster(name)
+	}
+
+	return errors.Wrapf(dbaas.ErrNotFound, "backup schedule %s", schedule)
+}
+
+func (p *PXC) loadCluster(name string) error {
+	err := p.setVersionObjectsWithDefaults(Version(""))
+	if err != nil {
+		return errors.Wrap(err, "version check")
+	}
+	cluster, err := p.cmd.GetObject("pxc", name)
+	if err != nil {
+		return errors.Wrap(err, "get cluster object")
+	}
+
+	return errors.Wrap(json.Unmarshal(cluster, p.conf), "unmarshal object")
+}
+
+func (p *PXC) applyCluster(name string) error {
+	cr, err := p.getCR(p.conf)
+	if err != nil {
+		return errors.Wrap(err, "get cr")
+	}
+
+	return errors.Wrap(p.cmd.Upgrade("pxc", name, cr), "upgrade cluster")
+}
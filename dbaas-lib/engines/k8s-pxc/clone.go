@@ -0,0 +1,159 @@
+package pxc
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+const (
+	backupType  = "perconaxtradbclusterbackup.pxc.percona.com"
+	restoreType = "perconaxtradbclusterrestore.pxc.percona.com"
+)
+
+// CloneDBCluster creates the target cluster of the same version and with the same options as the source
+// one and copies the data into it. The labels and, unless target.KeepBackupSchedules is set, the backup
+// schedules of the source aren't copied. The volumes are copied with snapshots if the CSI driver supports them,
+// otherwise the on-demand backup of the source is restored into the target. The backup is kept
+func (p *PXC) CloneDBCluster(source string, target dbaas.Instance, timeout time.Duration) error {
+	srcCR, err := p.cmd.GetObject("pxc", source)
+	if err != nil {
+		return errors.Wrap(err, "get source cluster")
+	}
+	version, err := k8s.CRVersion(srcCR)
+	if err != nil {
+		return err
+	}
+	err = p.setVersionObjectsWithDefaults(Version(version))
+	if err != nil {
+		return errors.Wrap(err, "version check")
+	}
+	err = json.Unmarshal(srcCR, p.conf)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal source cluster")
+	}
+	err = p.ParseOptions(target.EngineOptions)
+	if err != nil {
+		return errors.Wrap(err, "parse options")
+	}
+	p.conf.SetName(target.Name)
+	p.conf.SetLabels(target.Labels)
+	if !target.KeepBackupSchedules {
+		err = p.conf.SetBackupSchedules(nil)
+		if err != nil {
+			return errors.Wrap(err, "remove backup schedules")
+		}
+	}
+
+	// the data has the users of the source, so their passwords are copied as well
+	err = p.cmd.CopySecret(p.conf.GetUsersSecretName(), usersSecretName(target.Name))
+	if err != nil {
+		return errors.Wrap(err, "copy users secret")
+	}
+	p.conf.SetUsersSecretName(usersSecretName(target.Name))
+
+	// the certificates issued for the source hosts don't fit the clone, the custom ones are shared
+	if p.conf.GetTLSSecretName() == source+"-ssl" {
+		target.TLS = dbaas.TLSAuto
+		_, err = p.setupTLS(target)
+		if err != nil {
+			return errors.Wrap(err, "setup TLS")
+		}
+	}
+
+	cr, err := p.getCR(p.conf)
+	if err != nil {
+		return errors.Wrap(err, "get cr")
+	}
+	cr, err = k8s.CleanCR(cr)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := p.cmd.CloneVolumes("app.kubernetes.io/instance="+source+",app.kubernetes.io/component=pxc", source, target.Name, timeout)
+	switch err {
+	case nil:
+		err = p.createClone(target.Name, cr, timeout)
+		if err != nil {
+			return err
+		}
+		return errors.WithMessage(p.cmd.DeleteSnapshots(snapshots), "delete snapshots")
+	case k8s.ErrSnapshotsNotSupported:
+	default:
+		return errors.Wrap(err, "clone volumes")
+	}
+
+	storage, err := backupStorage(srcCR)
+	if err != nil {
+		return err
+	}
+	backup := target.Name + "-clone-" + k8s.GenRandString(5)
+	err = p.cmd.CreateObject(map[string]interface{}{
+		"apiVersion": "pxc.percona.com/v1",
+		"kind":       "PerconaXtraDBClusterBackup",
+		"metadata":   map[string]string{"name": backup},
+		"spec": map[string]string{
+			"pxcCluster":  source,
+			"storageName": storage,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "create backup")
+	}
+	err = p.cmd.WaitState(backupType, backup, []string{"Succeeded"}, []string{"Failed"}, timeout)
+	if err != nil {
+		return errors.Wrap(err, "backup source cluster")
+	}
+
+	err = p.createClone(target.Name, cr, timeout)
+	if err != nil {
+		return err
+	}
+
+	restore := target.Name + "-clone-" + k8s.GenRandString(5)
+	err = p.cmd.CreateObject(map[string]interface{}{
+		"apiVersion": "pxc.percona.com/v1",
+		"kind":       "PerconaXtraDBClusterRestore",
+		"metadata":   map[string]string{"name": restore},
+		"spec": map[string]string{
+			"pxcCluster": target.Name,
+			"backupName": backup,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "create restore")
+	}
+
+	return errors.WithMessage(p.cmd.WaitState(restoreType, restore, []string{"Succeeded"}, []string{"Failed"}, timeout), "restore backup")
+}
+
+func (p *PXC) createClone(name, cr string, timeout time.Duration) error {
+	err := p.cmd.CreateCluster("pxc", p.conf.GetOperatorImage(), name, cr, p.bundle)
+	if err != nil {
+		return errors.Wrap(err, "create cluster")
+	}
+
+	return errors.WithMessage(p.cmd.WaitState("pxc", name, []string{string(dbaas.StateReady)}, nil, timeout), "wait for cluster")
+}
+
+// backupStorage returns the storage for the on-demand backup of the cluster, the default one if it is defined
+func backupStorage(cr []byte) (string, error) {
+	storages, err := k8s.BackupStorages(cr)
+	if err != nil {
+		return "", err
+	}
+	if len(storages) == 0 {
+		return "", errors.New("volume snapshots aren't supported by the storage class and the source cluster has no backup storage")
+	}
+	for _, s := range storages {
+		if s == k8s.DefaultBcpStorageName {
+			return s, nil
+		}
+	}
+
+	return storages[0], nil
+}
@@ -14,7 +14,11 @@
 
 package pxc
 
-import "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+import (
+	"time"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
 
 // PXDBCluster represent interface for ckuster types
 type PXDBCluster interface {
@@ -24,12 +28,29 @@ type PXDBCluster interface {
 	MarshalRequests() error
 	GetCR() (string, error)
 	SetLabels(labels map[string]string)
+	GetLabels() map[string]string
+	GetAnnotations() map[string]string
+	SetAnnotations(annotations map[string]string)
 	SetName(name string)
 	SetUsersSecretName(name string)
+	GetUsersSecretName() string
+	SetTLSSecretsName(secret, internalSecret string) error
+	GetTLSSecretName() string
 	GetOperatorImage() string
 	SetupMiniConfig() //For Minikube and Minishift
 	GetProxysqlServiceType() string
 	GetStatus() dbaas.State
 	GetPXCStatus() string
+	GetComponents() []dbaas.ComponentStatus
+	GetImage() string
+	GetStorageSize() string
+	IsPaused() bool
+	GetCreationTime() time.Time
 	GetStatusHost() string
+	GetBackupSchedules() []dbaas.BackupSchedule
+	SetBackupSchedules(schedules []dbaas.BackupSchedule) error
+	GetBackupStorages() []dbaas.BackupStorage
+	SetBackupStorage(storage dbaas.BackupStorage) error
+	GetPMM() (enabled bool, serverHost, serverUser string)
+	SetPMM(enabled bool, serverHost, serverUser string)
 }
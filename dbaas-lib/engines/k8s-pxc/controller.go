@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"math/big"
 	mrand "math/rand"
+	"reflect"
 	"strings"
 	"time"
 
@@ -15,25 +16,83 @@ import (
 )
 
 // CreateDBCluster start creating DB cluster
-func (p *PXC) CreateDBCluster(name, opts, rootPass, version string) error {
-	err := p.setVersionObjectsWithDefaults(Version(version))
+func (p *PXC) CreateDBCluster(instance dbaas.Instance) (err error) {
+	name := instance.Name
+	err = p.setVersionObjectsWithDefaults(Version(instance.Version))
 	if err != nil {
 		return errors.Wrap(err, "version check")
 	}
-	err = p.ParseOptions(opts)
+	// the sizing set explicitly by the preset and options is kept on minikube and minishift
+	switch p.platformType {
+	case k8s.PlatformMinishift, k8s.PlatformMinikube:
+		p.conf.SetupMiniConfig()
+	}
+	if len(instance.Preset) > 0 {
+		err = p.applyPreset(instance.Preset)
+		if err != nil {
+			return errors.Wrap(err, "apply preset")
+		}
+	}
+	err = p.ParseOptions(instance.EngineOptions)
 	if err != nil {
 		return errors.Wrap(err, "parsing options")
 	}
 
 	p.conf.SetName(name)
-	p.conf.SetUsersSecretName(name)
-	switch p.platformType {
-	case k8s.PlatformMinishift, k8s.PlatformMinikube:
-		p.conf.SetupMiniConfig()
+	if len(instance.Preset) > 0 {
+		p.setAnnotation(dbaas.PresetAnnotation, instance.Preset)
+	}
+	for k, v := range instance.Annotations {
+		p.setAnnotation(k, v)
+	}
+	if len(instance.Labels) > 0 {
+		p.conf.SetLabels(instance.Labels)
 	}
 
-	if len(rootPass) > 0 {
-		err = p.SetupPasswords(name, rootPass)
+	if len(instance.UsersSecret) > 0 {
+		if len(instance.RootPass) > 0 {
+			return errors.New("root password can't be set together with the users secret")
+		}
+		err = p.checkUsersSecret(instance.UsersSecret)
+		if err != nil {
+			return errors.Wrap(err, "check users secret")
+		}
+		p.conf.SetUsersSecretName(instance.UsersSecret)
+	} else {
+		p.conf.SetUsersSecretName(usersSecretName(name))
+	}
+
+	if instance.Encryption {
+		return errors.New("data-at-rest encryption isn't supported for pxc")
+	}
+
+	if len(instance.FromPreserved) > 0 {
+		version := instance.Version
+		if len(version) == 0 {
+			version = string(defaultVersion)
+		}
+		err = p.setupFromPreserved(instance, version)
+		if err != nil {
+			return errors.Wrap(err, "setup preserved data")
+		}
+	}
+
+	tlsSecrets, err := p.setupTLS(instance)
+	if err != nil {
+		return errors.Wrap(err, "setup TLS")
+	}
+	// the certificates of the cluster that isn't created would be picked by the next one with the same name
+	defer func() {
+		if err == nil || len(tlsSecrets) == 0 {
+			return
+		}
+		if derr := p.cmd.DeleteSecrets(tlsSecrets); derr != nil {
+			err = errors.WithMessagef(err, "%v", derr)
+		}
+	}()
+
+	if len(instance.RootPass) > 0 {
+		err = p.SetupPasswords(name, instance.RootPass)
 		if err != nil {
 			return errors.Wrap(err, "set root password")
 		}
@@ -67,7 +126,7 @@ func (p *PXC) DeleteDBCluster(name, opts, version string, delePVC bool) (string,
 	}
 
 	if !ext {
-		return "", errors.New("unable to find cluster pxc/" + name)
+		return "", errors.Wrapf(dbaas.ErrNotFound, "cluster pxc/%s", name)
 	}
 
 	err = p.setVersionObjectsWithDefaults(Version(version))
@@ -75,27 +134,36 @@ func (p *PXC) DeleteDBCluster(name, opts, version string, delePVC bool) (string,
 		return "", errors.Wrap(err, "version check")
 	}
 
+	cluster, err := p.cmd.GetObject("pxc", name)
+	if err != nil {
+		return "", errors.Wrap(err, "get cluster object")
+	}
+	err = json.Unmarshal(cluster, p.conf)
+	if err != nil {
+		return "", errors.Wrap(err, "unmarshal object")
+	}
 	p.conf.SetName(name)
 
+	var volumes []string
+	if !delePVC {
+		volumes, err = p.cmd.PreserveVolumes(p.operatorName(), name, "pxc", cluster)
+		if err != nil {
+			return "", errors.Wrap(err, "preserve volumes")
+		}
+	}
 	err = p.cmd.DeleteCluster("pxc", p.operatorName(), name, delePVC)
 	if err != nil {
 		return "", errors.Wrap(err, "delete cluster")
 	}
 	if !delePVC {
-		pvcObj, err := p.cmd.GetObject("pvc", "datadir-"+name+"-pxc-0")
-		if err != nil {
-			return "", errors.Wrap(err, "get pvc")
-		}
-		pvc := &corev1.PersistentVolumeClaim{}
-		err = json.Unmarshal(pvcObj, pvc)
+		return strings.Join(volumes, ", "), nil
+	}
+	// a secret provided by the user is not owned by the cluster, so it outlives it
+	if p.conf.GetUsersSecretName() == usersSecretName(name) {
+		err = p.cmd.DeleteObject("secret", usersSecretName(name))
 		if err != nil {
-			return "", errors.Wrap(err, "unmarshal pvc")
+			return "", errors.Wrap(err, "delete secret")
 		}
-		return "pvc/" + pvc.Name, nil
-	}
-	err = p.cmd.DeleteObject("secret", name+"-secrets")
-	if err != nil {
-		return "", errors.Wrap(err, "delete secret")
 	}
 
 	return "", nil
@@ -108,11 +176,6 @@ func (p *PXC) GetDBCluster(name, opts string) (dbaas.DB, error) {
 	if err != nil {
 		return db, errors.Wrap(err, "version check")
 	}
-	secrets, err := p.cmd.GetSecrets(name + "-secrets")
-	if err != nil {
-		return db, errors.Wrap(err, "get cluster secrets")
-
-	}
 	cluster, err := p.cmd.GetObject("pxc", name)
 	if err != nil {
 		return db, errors.Wrap(err, "get cluster object")
@@ -124,6 +187,11 @@ func (p *PXC) GetDBCluster(name, opts string) (dbaas.DB, error) {
 	if err != nil {
 		return db, errors.Wrap(err, "unmarshal object")
 	}
+	secrets, err := p.cmd.GetSecrets(st.GetUsersSecretName())
+	if err != nil {
+		return db, errors.Wrap(err, "get cluster secrets")
+
+	}
 	err = p.checkClusterPods(name)
 	if err != nil {
 		db.Status = "error"
@@ -142,6 +210,19 @@ func (p *PXC) GetDBCluster(name, opts string) (dbaas.DB, error) {
 	db.Pass = string(secrets["root"])
 	db.ResourceEndpoint = st.GetStatusHost() + "." + ns + "pxc.svc.local"
 	db.Status = st.GetStatus()
+	db.Preset = st.GetAnnotations()[dbaas.PresetAnnotation]
+	db.Monitoring = p.monitoringStatus(name)
+	p.setDetails(&db, cluster)
+	db.CAFingerprint, err = p.caFingerprint()
+	if err != nil && db.Status == dbaas.StateReady {
+		return db, errors.Wrap(err, "get CA fingerprint")
+	}
+	// the CA has to be fetched from the secret to verify the server certificate
+	getCA, sslOpts := "", ""
+	if len(db.CAFingerprint) > 0 {
+		getCA = "kubectl get secret " + st.GetTLSSecretName() + " -o jsonpath='{.data.ca\\.crt}' | base64 --decode > ca.crt\n"
+		sslOpts = " --ssl-mode=VERIFY_CA --ssl-ca=ca.crt"
+	}
 	if p.conf.GetProxysqlServiceType() == "LoadBalancer" {
 		svc := corev1.Service{}
 		svcData, err := p.cmd.GetObject("svc", name+"-proxysql")
@@ -159,17 +240,17 @@ func (p *PXC) GetDBCluster(name, opts string) (dbaas.DB, error) {
 			}
 		}
 		if st.GetStatus() == dbaas.StateReady {
-			db.Message = "To access database please run the following command:\nmysql -h " + db.ResourceEndpoint + " -P 3306 -uroot -pPASSWORD"
+			db.Message = "To access database please run the following command:\n" + getCA + "mysql -h " + db.ResourceEndpoint + " -P 3306 -uroot -pPASSWORD" + sslOpts
 		}
 		return db, nil
 	}
 
 	if st.GetStatus() == dbaas.StateReady {
-		db.Message = "To access database please run the following commands:\nkubectl port-forward svc/" + name + "-proxysql 3306:3306 &\nmysql -h 127.0.0.1 -P 3306 -uroot -pPASSWORD"
+		db.Message = "To access database please run the following commands:\n" + getCA + "kubectl port-forward svc/" + name + "-proxysql 3306:3306 &\nmysql -h 127.0.0.1 -P 3306 -uroot -pPASSWORD" + sslOpts
 	}
 	if st.GetStatus() == dbaas.StateUnknown && st.GetPXCStatus() == string(dbaas.StateReady) {
 		db.Status = dbaas.StateReady
-		db.Message = "To access database please run the following commands:\nkubectl port-forward pod/" + name + "-pxc-0 3306:3306 &\nmysql -h 127.0.0.1 -P 3306 -uroot -pPASSWORD"
+		db.Message = "To access database please run the following commands:\n" + getCA + "kubectl port-forward pod/" + name + "-pxc-0 3306:3306 &\nmysql -h 127.0.0.1 -P 3306 -uroot -pPASSWORD" + sslOpts
 	}
 
 	return db, nil
@@ -179,9 +260,11 @@ func (p *PXC) GetDBCluster(name, opts string) (dbaas.DB, error) {
 func (p *PXC) GetDBClusterList() ([]dbaas.DB, error) {
 	var dbList []dbaas.DB
 	cluster, err := p.cmd.GetObjects("pxc")
-	if err != nil {
+	if err == k8s.ErrNotFound {
+		// there are no clusters or the operator custom resource isn't installed
+		return dbList, nil
+	} else if err != nil {
 		return dbList, errors.Wrap(err, "get cluster object")
-
 	}
 	st := k8s.Clusters{}
 	err = json.Unmarshal(cluster, &st)
@@ -192,13 +275,18 @@ func (p *PXC) GetDBClusterList() ([]dbaas.DB, error) {
 	if err != nil {
 		return dbList, errors.Wrap(err, "version check")
 	}
+	// every cluster is read into the fresh object, so the fields missing in its
+	// custom resource aren't taken from the previous one
+	conf := p.conf
+	defer func() { p.conf = conf }()
 	for _, c := range st.Items {
 		b, err := json.Marshal(c)
 		if err != nil {
 			return dbList, errors.Wrap(err, "marshal")
 		}
 
-		err = json.Unmarshal(b, &p.conf)
+		p.conf = reflect.New(reflect.TypeOf(conf).Elem()).Interface().(PXDBCluster)
+		err = json.Unmarshal(b, p.conf)
 		if err != nil {
 			return dbList, errors.Wrap(err, "unmarshal pxc object")
 		}
@@ -207,6 +295,7 @@ func (p *PXC) GetDBClusterList() ([]dbaas.DB, error) {
 			ResourceName: pxc.GetName(),
 			Status:       pxc.GetStatus(),
 		}
+		p.setDetails(&db, b)
 		dbList = append(dbList, db)
 	}
 
@@ -234,7 +323,6 @@ func (p *PXC) UpdateDBCluster(name, opts, version string) error {
 		return errors.Wrap(err, "parse options")
 	}
 	p.conf.SetName(name)
-	p.conf.SetUsersSecretName(name)
 
 	cr, err := p.getCR(p.conf)
 	if err != nil {
@@ -250,7 +338,7 @@ func (p *PXC) UpdateDBCluster(name, opts, version string) error {
 }
 
 func (p *PXC) SetupPasswords(clusterName, rootPass string) error {
-	secretName := clusterName + "-secrets"
+	secretName := usersSecretName(clusterName)
 	ext, err := p.cmd.IsObjExists("secret", secretName)
 	if err != nil {
 		return errors.Wrap(err, "check if secrets exists")
@@ -300,6 +388,31 @@ func (p *PXC) SetupPasswords(clusterName, rootPass string) error {
 	return nil
 }
 
+// usersSecretKeys are the keys the operator expects to find in the users secret
+var usersSecretKeys = []string{"root", "xtrabackup", "monitor", "clustercheck", "proxyadmin"}
+
+func usersSecretName(clusterName string) string {
+	return clusterName + "-secrets"
+}
+
+func (p *PXC) checkUsersSecret(secretName string) error {
+	data, err := p.cmd.GetSecrets(secretName)
+	if err != nil {
+		return errors.Wrapf(err, "get secret %s", secretName)
+	}
+	var missing []string
+	for _, k := range usersSecretKeys {
+		if len(data[k]) == 0 {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("secret %s has no values for keys: %s", secretName, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
 const (
 	passwordMaxLen = 20
 	passwordMinLen = 16
@@ -355,7 +468,7 @@ func (p *PXC) checkClusterPods(name string) error {
 		return err
 	}
 
-	podsData, err = p.cmd.GetObjectByLables("pods", "app.kubernetes.io/instance=cluster1,app.kubernetes.io/component=proxysql")
+	podsData, err = p.cmd.GetObjectByLables("pods", "app.kubernetes.io/instance="+name+",app.kubernetes.io/component=proxysql")
 	if err != nil {
 		return errors.Wrap(err, "get pods")
 	}
@@ -374,18 +487,8 @@ func checkPodsCondition(podsData []byte) error {
 	if err != nil {
 		return errors.Wrap(err, "unmarshal pods data")
 	}
-	for _, pod := range pods.Items {
-		if pod.Status.Phase != "Pending" {
-			return nil
-		}
-		for _, condition := range pod.Status.Conditions {
-			if condition.Status == "False" && strings.Contains(condition.Message, "Insufficient memory") {
-				return k8s.ErrOutOfMemory
-			}
-		}
-	}
 
-	return nil
+	return k8s.PodsProblem(pods.Items)
 }
 
 func (p *PXC) getNamespace() (string, error) {
@@ -413,3 +516,41 @@ func (p *PXC) getOperatorVersion() string {
 
 	return ""
 }
+
+// applyPreset sets the cluster components sizing from the preset
+func (p *PXC) applyPreset(name string) error {
+	preset, err := dbaas.GetPreset(name)
+	if err != nil {
+		return err
+	}
+	var opts []string
+	opts = append(opts, preset.Components["pxc"].Options("spec.pxc")...)
+	opts = append(opts, preset.Components["proxysql"].Options("spec.proxysql")...)
+
+	return p.ParseOptions(strings.Join(opts, ","))
+}
+
+func (p *PXC) setAnnotation(key, value string) {
+	annotations := p.conf.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	p.conf.SetAnnotations(annotations)
+}
+
+// setDetails sets the versions, the components status, the storage size, the age and the backup
+// schedules of the cluster from the loaded object and its custom resource
+func (p *PXC) setDetails(db *dbaas.DB, cr []byte) {
+	db.Provider = provider
+	db.Engine = engine
+	db.EngineVersion = k8s.ImageVersion(p.conf.GetImage(), "-pxc")
+	db.OperatorVersion, _ = k8s.CRVersion(cr)
+	db.Size = p.conf.GetStorageSize()
+	db.Components = p.conf.GetComponents()
+	db.Created = p.conf.GetCreationTime()
+	db.Paused = p.conf.IsPaused()
+	db.BackupSchedules = p.conf.GetBackupSchedules()
+	db.Labels = p.conf.GetLabels()
+	db.Annotations = dbaas.DBAnnotations(p.conf.GetAnnotations())
+}
@@ -0,0 +1,133 @@
+package pxc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// fakeKubectl answers with the cluster "src" and saves the applied objects into the dir
+const fakeKubectl = `#!/bin/sh
+case "$*" in
+"get pxc/src -o json") cat "$FAKE_KUBECTL_DIR/src.json" ;;
+"get secrets/"*) echo '{"data":{"root":"cm9vdA=="}}' ;;
+"get perconaxtradbcluster.pxc.percona.com dst -o name") echo 'Error from server (NotFound): not found'; exit 1 ;;
+"apply -f "*) cp "$3" "$FAKE_KUBECTL_DIR/applied.json" ;;
+"config view"*) echo default ;;
+*) echo '{"items":[]}' ;;
+esac
+`
+
+const srcCR = `{
+	"apiVersion": "pxc.percona.com/v1-4-0",
+	"kind": "PerconaXtraDBCluster",
+	"metadata": {
+		"name": "src",
+		"uid": "0a1b2c",
+		"resourceVersion": "42",
+		"labels": {"team": "payments"},
+		"annotations": {"dbaas.percona.com/broker-instance": "instance-id"}
+	},
+	"spec": {
+		"sslSecretName": "src-ssl",
+		"pxc": {"size": 5},
+		"backup": {"schedule": [{"name": "daily", "schedule": "0 0 * * *", "storageName": "s3"}]}
+	},
+	"status": {"state": "initializing", "host": "src-proxysql"}
+}`
+
+// useFakeKubectl puts the kubectl script and the files into the temp dir, which is added to PATH
+// and passed to the script as FAKE_KUBECTL_DIR. The returned function restores the environment
+func useFakeKubectl(t *testing.T, script string, files map[string]string) (string, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake kubectl is a shell script")
+	}
+	dir, err := ioutil.TempDir("", "fake-kubectl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["kubectl"] = script
+	for name, data := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0755)
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	os.Setenv("FAKE_KUBECTL_DIR", dir)
+
+	return dir, func() {
+		os.Setenv("PATH", path)
+		os.Unsetenv("FAKE_KUBECTL_DIR")
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCreateAfterDescribe(t *testing.T) {
+	dir, cleanup := useFakeKubectl(t, fakeKubectl, map[string]string{"src.json": srcCR})
+	defer cleanup()
+
+	p, err := NewPXCController("", "k8s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := p.GetDBCluster("src", "")
+	if err != nil {
+		t.Fatal("describe: ", err)
+	}
+	if db.Components[0].Size != 5 {
+		t.Fatalf("the cluster isn't read: %+v", db)
+	}
+
+	err = p.CreateDBCluster(dbaas.Instance{Name: "dst"})
+	if err != nil {
+		t.Fatal("create: ", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "applied.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cr struct {
+		Metadata struct {
+			Name            string            `json:"name"`
+			UID             string            `json:"uid"`
+			ResourceVersion string            `json:"resourceVersion"`
+			Labels          map[string]string `json:"labels"`
+			Annotations     map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec struct {
+			SSLSecretName string `json:"sslSecretName"`
+			PXC           struct {
+				Size int `json:"size"`
+			} `json:"pxc"`
+			Backup struct {
+				Schedule []interface{} `json:"schedule"`
+			} `json:"backup"`
+		} `json:"spec"`
+		Status struct {
+			State string `json:"state"`
+			Host  string `json:"host"`
+		} `json:"status"`
+	}
+	err = json.Unmarshal(data, &cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr.Metadata.Name != "dst" || len(cr.Metadata.UID) > 0 || len(cr.Metadata.ResourceVersion) > 0 ||
+		len(cr.Metadata.Labels) > 0 || len(cr.Metadata.Annotations) > 0 {
+		t.Errorf("the new cluster has the metadata of the described one: %+v", cr.Metadata)
+	}
+	if len(cr.Spec.SSLSecretName) > 0 || len(cr.Spec.Backup.Schedule) > 0 || cr.Spec.PXC.Size != 3 {
+		t.Errorf("the new cluster has the spec of the described one: %+v", cr.Spec)
+	}
+	if len(cr.Status.State) > 0 || len(cr.Status.Host) > 0 {
+		t.Errorf("the new cluster has the status of the described one: %+v", cr.Status)
+	}
+}
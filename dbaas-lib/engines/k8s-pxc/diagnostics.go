@@ -0,0 +1,25 @@
+package pxc
+
+import "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+
+// CollectDiagnostics gathers the state of the cluster and the operator, the secrets values are left out
+func (p *PXC) CollectDiagnostics(name string) ([]dbaas.DiagnosticsFile, error) {
+	list, err := p.cmd.CollectDiagnostics("pxc", p.operatorName(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]dbaas.DiagnosticsFile, 0, len(list))
+	for _, d := range list {
+		f := dbaas.DiagnosticsFile{
+			Name: d.Name,
+			Data: d.Data,
+		}
+		if d.Err != nil {
+			f.Error = d.Err.Error()
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,37 @@
+package pxc
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// StreamLogs writes the logs of the cluster pods of the component and the operator log lines mentioning the cluster to w
+func (p *PXC) StreamLogs(name string, logs dbaas.Logs, w io.Writer) error {
+	ext, err := p.cmd.IsObjExists("pxc", name)
+	if err != nil {
+		return errors.Wrap(err, "check if cluster exists")
+	}
+	if !ext {
+		return errors.Wrapf(dbaas.ErrNotFound, "cluster pxc/%s", name)
+	}
+
+	labels := "app.kubernetes.io/instance=" + name + ",app.kubernetes.io/component"
+	operator := p.operatorName()
+	switch logs.Component {
+	case "":
+		labels += " in (pxc,proxysql)"
+	case "operator":
+		labels = ""
+	case "pxc", "proxysql":
+		labels += "=" + logs.Component
+		operator = ""
+	default:
+		return errors.Errorf("unknown component %s, use pxc, proxysql or operator", logs.Component)
+	}
+
+	return p.cmd.StreamLogs(w, labels, operator, name, k8s.LogsOptions{Follow: logs.Follow, Since: logs.Since})
+}
@@ -0,0 +1,106 @@
+package pxc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+const (
+	pmmContainer = "pmm-client"
+	// pmmPasswordKey is the key of the PMM server password in the users secret the operator reads
+	pmmPasswordKey = "pmmserver"
+	// pmmPodsLabels select the pods the operator adds the PMM client sidecar to
+	pmmPodsLabels = "app.kubernetes.io/component in (pxc,proxysql),app.kubernetes.io/instance="
+)
+
+// EnableMonitoring adds the PMM client sidecars to the cluster pods. The PMM server password is copied
+// to the cluster users secret from the given secret
+func (p *PXC) EnableMonitoring(name string, m dbaas.Monitoring) error {
+	err := p.loadCluster(name)
+	if err != nil {
+		return err
+	}
+	_, host, user := p.conf.GetPMM()
+	if len(m.ServerHost) > 0 {
+		host = m.ServerHost
+	}
+	if len(m.ServerUser) > 0 {
+		user = m.ServerUser
+	}
+	if len(host) == 0 || len(user) == 0 {
+		return errors.New("PMM server host and user are required")
+	}
+
+	usersSecret := p.conf.GetUsersSecretName()
+	secrets, err := p.cmd.GetSecrets(usersSecret)
+	if err != nil {
+		return errors.Wrap(err, "get cluster secrets")
+	}
+	if len(m.PasswordSecret) > 0 {
+		pass, err := p.pmmPassword(m.PasswordSecret)
+		if err != nil {
+			return err
+		}
+		secrets[pmmPasswordKey] = pass
+		err = p.cmd.UpdateSecrets(usersSecret, secrets)
+		if err != nil {
+			return errors.Wrap(err, "update cluster secrets")
+		}
+	} else if len(secrets[pmmPasswordKey]) == 0 {
+		return errors.New("PMM server password secret is required")
+	}
+
+	p.conf.SetPMM(true, host, user)
+	err = p.applyCluster(name)
+	if err != nil || m.Wait == 0 {
+		return err
+	}
+
+	return p.cmd.WaitContainers(pmmPodsLabels+name, pmmContainer, true, m.Wait)
+}
+
+// DisableMonitoring removes the PMM client sidecars from the cluster pods
+func (p *PXC) DisableMonitoring(name string, wait time.Duration) error {
+	err := p.loadCluster(name)
+	if err != nil {
+		return err
+	}
+	p.conf.SetPMM(false, "", "")
+	err = p.applyCluster(name)
+	if err != nil || wait == 0 {
+		return err
+	}
+
+	return p.cmd.WaitContainers(pmmPodsLabels+name, pmmContainer, false, wait)
+}
+
+func (p *PXC) pmmPassword(secret string) ([]byte, error) {
+	data, err := p.cmd.GetSecrets(secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get secret %s", secret)
+	}
+	pass := data["password"]
+	if len(pass) == 0 {
+		return nil, errors.Errorf("secret %s has no password key", secret)
+	}
+
+	return pass, nil
+}
+
+// monitoringStatus describes the PMM client of the cluster for describe-db
+func (p *PXC) monitoringStatus(name string) string {
+	enabled, host, _ := p.conf.GetPMM()
+	if !enabled {
+		return "disabled"
+	}
+	ready, total, err := p.cmd.ContainersReady(pmmPodsLabels+name, pmmContainer)
+	if err != nil {
+		return "enabled, PMM server " + host
+	}
+
+	return fmt.Sprintf("enabled, PMM server %s, %d/%d clients ready", host, ready, total)
+}
@@ -0,0 +1,79 @@
+package pxc
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// ListPreservedData returns the data left by the deleted PXC clusters
+func (p *PXC) ListPreservedData() ([]dbaas.PreservedData, error) {
+	list, err := p.cmd.ListPreservedData("pxc", p.operatorName())
+	if err != nil {
+		return nil, errors.Wrap(err, "list preserved data")
+	}
+
+	data := make([]dbaas.PreservedData, 0, len(list))
+	for _, d := range list {
+		data = append(data, dbaas.PreservedData{
+			Cluster:     d.Cluster,
+			Engine:      d.Engine,
+			Version:     d.Version,
+			Volumes:     d.Volumes,
+			Size:        d.Size.String(),
+			Created:     d.Created,
+			PreservedAt: d.PreservedAt,
+		})
+	}
+
+	return data, nil
+}
+
+// PurgePreservedData deletes the volumes of the deleted cluster and the users secret kept along with them
+func (p *PXC) PurgePreservedData(name string) error {
+	err := p.cmd.PurgePreservedData("pxc", p.operatorName(), name)
+	if err != nil {
+		return err
+	}
+	ext, err := p.cmd.IsObjExists("secret", usersSecretName(name))
+	if err != nil {
+		return errors.Wrap(err, "check if secret exists")
+	}
+	if !ext {
+		return nil
+	}
+
+	return errors.WithMessage(p.cmd.DeleteObject("secret", usersSecretName(name)), "delete secret")
+}
+
+// setupFromPreserved checks that the data of the deleted cluster can be used by the new one of
+// the version and moves it to the new cluster if the name differs. The users passwords are stored
+// in the data, so the users secret of the deleted cluster is moved as well
+func (p *PXC) setupFromPreserved(instance dbaas.Instance, version string) error {
+	if len(instance.RootPass) > 0 {
+		return errors.New("root password can't be set for the cluster created from preserved data")
+	}
+	d, err := p.cmd.GetPreservedData("pxc", instance.FromPreserved)
+	if err == k8s.ErrNotFound {
+		return errors.Wrapf(dbaas.ErrNotFound, "preserved data of %s", instance.FromPreserved)
+	} else if err != nil {
+		return errors.Wrap(err, "get preserved data")
+	}
+	err = d.Check(p.operatorName(), version)
+	if err != nil {
+		return err
+	}
+	if instance.FromPreserved == instance.Name {
+		return nil
+	}
+
+	if len(instance.UsersSecret) == 0 {
+		err = p.cmd.MoveSecret(usersSecretName(instance.FromPreserved), usersSecretName(instance.Name))
+		if err != nil {
+			return errors.Wrap(err, "move users secret")
+		}
+	}
+
+	return errors.WithMessage(p.cmd.RebindPreservedData(d, instance.Name), "move volumes")
+}
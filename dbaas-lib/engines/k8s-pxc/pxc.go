@@ -17,6 +17,7 @@ package pxc
 import (
 	"fmt"
 	"os"
+	"reflect"
 
 	"github.com/pkg/errors"
 
@@ -104,22 +105,21 @@ func NewPXCController(envCrt, provider string) (*PXC, error) {
 }
 
 func (p *PXC) setVersionObjectsWithDefaults(version Version) error {
-	if p.conf != nil && p.bundle != nil {
-		return nil
-	}
 	switch i := len(version); {
 	case i == 0:
 		version = defaultVersion
 	default:
 		if _, ok := objects[version]; !ok {
-			return errors.Errorf("unsupporeted version %s", version)
+			return errors.Wrapf(dbaas.ErrUnsupportedVersion, "operator version %s", version)
 		}
 	}
 
-	p.conf = objects[version].pxc
+	// the engine is shared by the calls, so every one starts with the fresh object of the version
+	// rather than the one the previous call has read the other cluster into
+	p.conf = reflect.New(reflect.TypeOf(objects[version].pxc).Elem()).Interface().(PXDBCluster)
 	err := p.conf.SetDefaults()
 	if err != nil {
-		errors.Wrap(err, "set defaults")
+		return errors.Wrap(err, "set defaults")
 	}
 	p.bundle = objects[version].k8s.Bundle
 
@@ -0,0 +1,114 @@
+package pxc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/k8s"
+)
+
+// pitrSinceVersion is the first operator version collecting binlogs for point-in-time recovery
+const pitrSinceVersion = "1.8.0"
+
+type backup struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		PXCCluster  string `json:"pxcCluster"`
+		StorageName string `json:"storageName"`
+	} `json:"spec"`
+	Status struct {
+		State     string       `json:"state"`
+		Completed *metav1.Time `json:"completed"`
+	} `json:"status"`
+}
+
+// RestoreDBCluster restores the cluster data from the backup. If the backup isn't given the latest
+// backup completed before restore.ToTime is selected. The data can't be recovered to the time after
+// the backup as binlogs aren't collected by the supported operator versions, so the restore fails
+// with dbaas.ErrDataLoss unless restore.AcceptDataLoss is set, the returned message tells about the loss then
+func (p *PXC) RestoreDBCluster(name string, restore dbaas.Restore) (string, error) {
+	ext, err := p.cmd.IsObjExists("pxc", name)
+	if err != nil {
+		return "", errors.Wrap(err, "check if cluster exists")
+	}
+	if !ext {
+		return "", errors.Wrapf(dbaas.ErrNotFound, "cluster pxc/%s", name)
+	}
+
+	msg := ""
+	bcp := restore.Backup
+	if len(bcp) == 0 {
+		b, err := p.SelectBackup(name, restore.ToTime)
+		if err != nil {
+			return "", err
+		}
+		bcp = b.Name
+		if restore.ToTime.After(b.Completed) {
+			lost := fmt.Sprintf("the backup %s completed at %s, the changes made after it until %s are lost. Point-in-time recovery needs binlogs, which are collected since operator version %s",
+				b.Name, b.Completed.UTC().Format(time.RFC3339), restore.ToTime.UTC().Format(time.RFC3339), pitrSinceVersion)
+			if !restore.AcceptDataLoss {
+				return "", errors.Wrap(dbaas.ErrDataLoss, lost)
+			}
+			msg = "The data is restored as of " + lost
+		}
+	}
+
+	restoreName := name + "-restore-" + k8s.GenRandString(5)
+	err = p.cmd.CreateObject(map[string]interface{}{
+		"apiVersion": "pxc.percona.com/v1",
+		"kind":       "PerconaXtraDBClusterRestore",
+		"metadata":   map[string]string{"name": restoreName},
+		"spec": map[string]string{
+			"pxcCluster": name,
+			"backupName": bcp,
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "create restore")
+	}
+	if restore.Wait == 0 {
+		return msg, nil
+	}
+
+	return msg, p.cmd.WaitState(restoreType, restoreName, []string{"Succeeded"}, []string{"Failed"}, restore.Wait)
+}
+
+// SelectBackup returns the latest successful backup of the cluster completed not after the time
+func (p *PXC) SelectBackup(name string, before time.Time) (dbaas.Backup, error) {
+	data, err := p.cmd.GetObjects(backupType)
+	if err != nil && err != k8s.ErrNotFound {
+		return dbaas.Backup{}, errors.Wrap(err, "get backups")
+	}
+	var list struct {
+		Items []backup `json:"items"`
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &list)
+		if err != nil {
+			return dbaas.Backup{}, errors.Wrap(err, "unmarshal backups")
+		}
+	}
+
+	var latest *backup
+	for i, b := range list.Items {
+		if b.Spec.PXCCluster != name || b.Status.State != "Succeeded" || b.Status.Completed == nil {
+			continue
+		}
+		if b.Status.Completed.Time.After(before) {
+			continue
+		}
+		if latest == nil || b.Status.Completed.Time.After(latest.Status.Completed.Time) {
+			latest = &list.Items[i]
+		}
+	}
+	if latest == nil {
+		return dbaas.Backup{}, errors.Wrapf(dbaas.ErrNotFound, "successful backup of %s completed before %s", name, before.Format(time.RFC3339))
+	}
+
+	return dbaas.Backup{Name: latest.Name, Completed: latest.Status.Completed.Time}, nil
+}
@@ -0,0 +1,74 @@
+package pxc
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// fakeBackupsKubectl answers with the cluster "db" and its backups, the applied restore is saved into the dir
+const fakeBackupsKubectl = `#!/bin/sh
+case "$*" in
+"get perconaxtradbcluster.pxc.percona.com db -o name") echo 'perconaxtradbcluster.pxc.percona.com/db' ;;
+"get perconaxtradbclusterbackup.pxc.percona.com -o json") cat "$FAKE_KUBECTL_DIR/backups.json" ;;
+"apply -f "*) cp "$3" "$FAKE_KUBECTL_DIR/applied.json" ;;
+"config view"*) echo default ;;
+*) echo '{"items":[]}' ;;
+esac
+`
+
+const backupList = `{"items": [
+	{"metadata": {"name": "daily-1"}, "spec": {"pxcCluster": "db"}, "status": {"state": "Succeeded", "completed": "2026-10-01T00:10:00Z"}},
+	{"metadata": {"name": "daily-2"}, "spec": {"pxcCluster": "db"}, "status": {"state": "Succeeded", "completed": "2026-10-02T00:10:00Z"}},
+	{"metadata": {"name": "failed"}, "spec": {"pxcCluster": "db"}, "status": {"state": "Failed", "completed": "2026-10-02T06:00:00Z"}},
+	{"metadata": {"name": "other"}, "spec": {"pxcCluster": "other"}, "status": {"state": "Succeeded", "completed": "2026-10-02T06:00:00Z"}}
+]}`
+
+func TestRestoreToTime(t *testing.T) {
+	dir, cleanup := useFakeKubectl(t, fakeBackupsKubectl, map[string]string{"backups.json": backupList})
+	defer cleanup()
+
+	p, err := NewPXCController("", "k8s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	toTime := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+	b, err := p.SelectBackup("db", toTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Name != "daily-2" || !b.Completed.Equal(time.Date(2026, 10, 2, 0, 10, 0, 0, time.UTC)) {
+		t.Errorf("selected backup: %+v", b)
+	}
+
+	_, err = p.RestoreDBCluster("db", dbaas.Restore{ToTime: toTime})
+	if !errors.Is(err, dbaas.ErrDataLoss) {
+		t.Fatalf("restore with the data loss: got %v", err)
+	}
+	if _, err := ioutil.ReadFile(filepath.Join(dir, "applied.json")); err == nil {
+		t.Error("the restore is created although the data loss isn't accepted")
+	}
+
+	msg, err := p.RestoreDBCluster("db", dbaas.Restore{ToTime: toTime, AcceptDataLoss: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(msg, "daily-2") {
+		t.Errorf("message doesn't name the backup: %q", msg)
+	}
+	applied, err := ioutil.ReadFile(filepath.Join(dir, "applied.json"))
+	if err != nil || !strings.Contains(string(applied), `"backupName":"daily-2"`) {
+		t.Errorf("applied restore: %s, %v", applied, err)
+	}
+
+	_, err = p.SelectBackup("db", time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC))
+	if !errors.Is(err, dbaas.ErrNotFound) {
+		t.Errorf("backup before the first one: got %v", err)
+	}
+}
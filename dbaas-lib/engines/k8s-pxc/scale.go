@@ -0,0 +1,112 @@
+package pxc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// ScaleDBCluster changes the number of PXC nodes and ProxySQL pods. It checks that the nodes
+// can fit the new pods and waits until they are ready if scale.Wait is set
+func (p *PXC) ScaleDBCluster(name string, scale dbaas.Scale) error {
+	if scale.Replicas < 1 {
+		return errors.New("number of replicas should be positive")
+	}
+	if scale.Proxies < 0 {
+		return errors.New("number of proxies can't be negative")
+	}
+	if len(scale.Replset) > 0 {
+		return errors.New("pxc clusters have no replsets")
+	}
+	if scale.Replicas%2 == 0 && !scale.Force {
+		return errors.Errorf("Galera cluster of %d nodes can't keep the quorum in case of the network split, use an odd number of nodes or force it", scale.Replicas)
+	}
+
+	err := p.setVersionObjectsWithDefaults(Version(""))
+	if err != nil {
+		return errors.Wrap(err, "version check")
+	}
+	cluster, err := p.cmd.GetObject("pxc", name)
+	if err != nil {
+		return errors.Wrap(err, "get cluster object")
+	}
+	err = json.Unmarshal(cluster, p.conf)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal object")
+	}
+
+	want := map[string]int32{"pxc": scale.Replicas}
+	if scale.Proxies > 0 {
+		want["proxysql"] = scale.Proxies
+	}
+	found := 0
+	for _, c := range p.conf.GetComponents() {
+		n, ok := want[c.Name]
+		if !ok {
+			continue
+		}
+		found++
+		err = p.cmd.CheckCapacity(int(n-c.Size), c.Requests.CPU, c.Requests.Memory)
+		if err != nil {
+			return errors.Wrapf(err, "check capacity for %s", c.Name)
+		}
+	}
+	if found < len(want) {
+		return errors.New("proxysql is disabled for the cluster")
+	}
+
+	var opts []string
+	for c, n := range want {
+		opts = append(opts, fmt.Sprintf("spec.%s.size=%d", c, n))
+	}
+	err = p.ParseOptions(strings.Join(opts, ","))
+	if err != nil {
+		return errors.Wrap(err, "parse options")
+	}
+	cr, err := p.getCR(p.conf)
+	if err != nil {
+		return errors.Wrap(err, "get cr")
+	}
+	err = p.cmd.Upgrade("pxc", name, cr)
+	if err != nil {
+		return errors.Wrap(err, "upgrade cluster")
+	}
+	if scale.Wait == 0 {
+		return nil
+	}
+
+	return p.waitScaled(name, want, scale.Wait)
+}
+
+// waitScaled waits until the status of the cluster components reports the wanted number of ready pods
+func (p *PXC) waitScaled(name string, want map[string]int32, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(5 * time.Second)
+
+		cluster, err := p.cmd.GetObject("pxc", name)
+		if err != nil {
+			continue
+		}
+		err = json.Unmarshal(cluster, p.conf)
+		if err != nil {
+			return errors.Wrap(err, "unmarshal object")
+		}
+		ready := true
+		for _, c := range p.conf.GetComponents() {
+			if n, ok := want[c.Name]; ok && c.Ready != n {
+				ready = false
+			}
+		}
+		if ready {
+			return nil
+		}
+	}
+
+	return errors.Errorf("cluster members aren't ready after %s", timeout)
+}
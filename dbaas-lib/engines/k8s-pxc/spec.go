@@ -0,0 +1,78 @@
+package pxc
+
+import (
+	"strings"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Options are the typed options of the pxc engine for dbaas.CreateDBWithSpec and dbaas.ModifyDBWithSpec.
+// The zero fields keep the defaults on create and the current values on modify
+type Options struct {
+	// Size is the number of the PXC nodes
+	Size      int32
+	Resources dbaas.ResourceRequirements
+	// Storage is the volume size of each node, e.g. "6G"
+	Storage      string
+	StorageClass string
+	// ProxySQL is the proxy in front of the nodes, nil keeps its defaults
+	ProxySQL *ProxySQL
+}
+
+// ProxySQL are the options of the ProxySQL pods
+type ProxySQL struct {
+	// Disabled turns the proxy off, the clients connect to the PXC nodes directly
+	Disabled  bool
+	Size      int32
+	Resources dbaas.ResourceRequirements
+	Storage   string
+}
+
+// Engine returns the name of the pxc engine
+func (o Options) Engine() string {
+	return engine
+}
+
+// EngineOptions returns the options in the "spec.pxc.size=3,..." format
+func (o Options) EngineOptions() (string, error) {
+	opts, err := componentOptions("spec.pxc", dbaas.Component{
+		Size:     o.Size,
+		Requests: o.Resources.Requests,
+		Limits:   o.Resources.Limits,
+		Storage:  o.Storage,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(o.StorageClass) > 0 {
+		opts = append(opts, "spec.pxc.volumespec.persistentvolumeclaim.storageclassname="+o.StorageClass)
+	}
+
+	if o.ProxySQL != nil {
+		if o.ProxySQL.Disabled {
+			return strings.Join(append(opts, "spec.proxysql.enabled=false"), ","), nil
+		}
+		proxy, err := componentOptions("spec.proxysql", dbaas.Component{
+			Size:     o.ProxySQL.Size,
+			Requests: o.ProxySQL.Resources.Requests,
+			Limits:   o.ProxySQL.Resources.Limits,
+			Storage:  o.ProxySQL.Storage,
+		})
+		if err != nil {
+			return "", err
+		}
+		opts = append(opts, "spec.proxysql.enabled=true")
+		opts = append(opts, proxy...)
+	}
+
+	return strings.Join(opts, ","), nil
+}
+
+func componentOptions(path string, c dbaas.Component) ([]string, error) {
+	err := c.Validate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Options(path), nil
+}
@@ -0,0 +1,66 @@
+package pxc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+	v140 "github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/engines/k8s-pxc/types/v140"
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/options"
+)
+
+func TestOptions(t *testing.T) {
+	o := Options{
+		Size:         5,
+		Resources:    dbaas.ResourceRequirements{Requests: dbaas.Resources{CPU: "1", Memory: "2G"}},
+		Storage:      "20G",
+		StorageClass: "fast",
+		ProxySQL:     &ProxySQL{Size: 2},
+	}
+	opts, err := o.EngineOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the options are the ones the engine parses
+	cr := &v140.PerconaXtraDBCluster{}
+	err = options.Parse(cr, reflect.TypeOf(cr), opts)
+	if err != nil {
+		t.Fatalf("parse %q: %v", opts, err)
+	}
+	if cr.Spec.PXC.Size != 5 || cr.Spec.PXC.Resources.Requests.CPU != "1" || *cr.Spec.PXC.VolumeSpec.PersistentVolumeClaim.StorageClassName != "fast" {
+		t.Errorf("unexpected pxc spec %+v from %q", cr.Spec.PXC, opts)
+	}
+	if !cr.Spec.ProxySQL.Enabled || cr.Spec.ProxySQL.Size != 2 {
+		t.Errorf("unexpected proxysql spec %+v from %q", cr.Spec.ProxySQL, opts)
+	}
+
+	opts, err = Options{ProxySQL: &ProxySQL{Disabled: true, Size: 2}}.EngineOptions()
+	if err != nil || opts != "spec.proxysql.enabled=false" {
+		t.Errorf("disabled proxysql: %q, %v", opts, err)
+	}
+	_, err = Options{Storage: "lots"}.EngineOptions()
+	if err == nil {
+		t.Error("expected the error for the invalid storage")
+	}
+}
+
+// The options are passed to dbaas.CreateDBWithSpec or dbaas.ModifyDBWithSpec,
+// which give the engine the same options in the text format
+func ExampleOptions() {
+	opts, err := Options{
+		Size: 3,
+		Resources: dbaas.ResourceRequirements{
+			Requests: dbaas.Resources{CPU: "1", Memory: "2G"},
+			Limits:   dbaas.Resources{CPU: "2", Memory: "4G"},
+		},
+		Storage:  "50G",
+		ProxySQL: &ProxySQL{Size: 2},
+	}.EngineOptions()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(opts)
+	// Output: spec.pxc.size=3,spec.pxc.resources.requests.cpu=1,spec.pxc.resources.requests.memory=2G,spec.pxc.resources.limits.cpu=2,spec.pxc.resources.limits.memory=4G,spec.pxc.volumespec.persistentvolumeclaim.resources.requests=storage:50G,spec.proxysql.enabled=true,spec.proxysql.size=2
+}
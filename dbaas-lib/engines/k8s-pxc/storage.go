@@ -0,0 +1,62 @@
+package pxc
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// ResizeStorage expands the volumes of PXC nodes. The operator doesn't resize volumes, so
+// the claims are patched directly and then the size is set in the CR for the new nodes.
+// The ProxySQL volumes keep their size. The returned message lists the volumes which file
+// systems are resized only when the pods are restarted
+func (p *PXC) ResizeStorage(name, size string, wait time.Duration) (string, error) {
+	err := p.setVersionObjectsWithDefaults(Version(""))
+	if err != nil {
+		return "", errors.Wrap(err, "version check")
+	}
+	cluster, err := p.cmd.GetObject("pxc", name)
+	if err != nil {
+		return "", errors.Wrap(err, "get cluster object")
+	}
+	err = json.Unmarshal(cluster, p.conf)
+	if err != nil {
+		return "", errors.Wrap(err, "unmarshal object")
+	}
+
+	restart, err := p.cmd.ExpandPVCs("app.kubernetes.io/instance="+name+",app.kubernetes.io/component=pxc", size, wait)
+	if err != nil {
+		return "", errors.Wrap(err, "expand volumes")
+	}
+	msg := ""
+	if len(restart) > 0 {
+		msg = "The file systems of the volumes " + strings.Join(restart, ", ") + " are resized when their pods are restarted"
+	}
+
+	err = p.ParseOptions(strings.Join(dbaas.Component{Storage: size}.Options("spec.pxc"), ","))
+	if err != nil {
+		return "", errors.Wrap(err, "parse options")
+	}
+	cr, err := p.getCR(p.conf)
+	if err != nil {
+		return "", errors.Wrap(err, "get cr")
+	}
+	err = p.cmd.Upgrade("pxc", name, cr)
+	if err != nil {
+		return msg, errors.Wrap(err, "upgrade cluster, the volumes are expanded already, run the resize again to finish it")
+	}
+	// volume claim templates of the statefulset can't be changed, so the operator recreates it
+	// from the updated CR while the running pods are kept. The CR is updated first, otherwise
+	// the operator could recreate the statefulset with the old size
+	sts := name + "-pxc"
+	err = p.cmd.DeleteOrphan("statefulset", sts)
+	if err != nil {
+		return msg, errors.Wrapf(err, "delete statefulset %s, the volumes are expanded, but the new members get the old size until it is deleted with --cascade=false", sts)
+	}
+
+	return msg, nil
+}
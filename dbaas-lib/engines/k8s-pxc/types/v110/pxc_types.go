@@ -2,6 +2,8 @@ package v110
 
 import (
 	"encoding/json"
+	"sort"
+	"time"
 
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 	"github.com/pkg/errors"
@@ -25,14 +27,26 @@ type PerconaXtraDBCluster struct {
 var defaultAffinityTopologyKey = "kubernetes.io/hostname"
 var affinityTopologyKeyOff = "none"
 
+const pmmImage = "percona/percona-xtradb-cluster-operator:1.1.0-pmm"
+
 func (cr *PerconaXtraDBCluster) GetName() string {
 	return cr.ObjectMeta.Name
 }
 
+// SetLabels sets the labels of the custom resource and of the pxc and proxysql pods
 func (cr *PerconaXtraDBCluster) SetLabels(labels map[string]string) {
+	for _, spec := range []*v1.PodSpec{cr.Spec.PXC, cr.Spec.ProxySQL} {
+		if spec != nil {
+			spec.Labels = dbaas.ReplaceLabels(spec.Labels, cr.ObjectMeta.Labels, labels)
+		}
+	}
 	cr.ObjectMeta.Labels = labels
 }
 
+func (cr *PerconaXtraDBCluster) GetLabels() map[string]string {
+	return cr.ObjectMeta.Labels
+}
+
 func (cr *PerconaXtraDBCluster) MarshalRequests() error {
 	_, err := cr.Spec.PXC.VolumeSpec.PersistentVolumeClaim.Resources.Requests[corev1.ResourceStorage].MarshalJSON()
 	return err
@@ -74,7 +88,19 @@ func (cr *PerconaXtraDBCluster) SetName(name string) {
 }
 
 func (cr *PerconaXtraDBCluster) SetUsersSecretName(name string) {
-	cr.Spec.SecretsName = name + "-secrets"
+	cr.Spec.SecretsName = name
+}
+
+func (cr *PerconaXtraDBCluster) GetUsersSecretName() string {
+	return cr.Spec.SecretsName
+}
+
+func (cr *PerconaXtraDBCluster) SetTLSSecretsName(secret, internalSecret string) error {
+	return errors.New("TLS configuration is supported since operator version 1.3.0")
+}
+
+func (cr *PerconaXtraDBCluster) GetTLSSecretName() string {
+	return ""
 }
 
 func (cr *PerconaXtraDBCluster) GetOperatorImage() string {
@@ -97,6 +123,57 @@ func (cr *PerconaXtraDBCluster) GetPXCStatus() string {
 	return string(cr.Status.PXC.Status)
 }
 
+// GetComponents returns the size, readiness and resource requests of the cluster components
+func (cr *PerconaXtraDBCluster) GetComponents() []dbaas.ComponentStatus {
+	var cs []dbaas.ComponentStatus
+	if cr.Spec.PXC != nil {
+		c := dbaas.ComponentStatus{Name: "pxc", Size: cr.Spec.PXC.Size, Ready: cr.Status.PXC.Ready}
+		if cr.Spec.PXC.Resources != nil && cr.Spec.PXC.Resources.Requests != nil {
+			c.Requests = dbaas.Resources{CPU: cr.Spec.PXC.Resources.Requests.CPU, Memory: cr.Spec.PXC.Resources.Requests.Memory}
+		}
+		cs = append(cs, c)
+	}
+	if cr.Spec.ProxySQL != nil && cr.Spec.ProxySQL.Enabled {
+		c := dbaas.ComponentStatus{Name: "proxysql", Size: cr.Spec.ProxySQL.Size, Ready: cr.Status.ProxySQL.Ready}
+		if cr.Spec.ProxySQL.Resources != nil && cr.Spec.ProxySQL.Resources.Requests != nil {
+			c.Requests = dbaas.Resources{CPU: cr.Spec.ProxySQL.Resources.Requests.CPU, Memory: cr.Spec.ProxySQL.Resources.Requests.Memory}
+		}
+		cs = append(cs, c)
+	}
+
+	return cs
+}
+
+// GetImage returns the image of the PXC nodes
+func (cr *PerconaXtraDBCluster) GetImage() string {
+	if cr.Spec.PXC == nil {
+		return ""
+	}
+
+	return cr.Spec.PXC.Image
+}
+
+// GetStorageSize returns the size of the data volumes of the PXC nodes
+func (cr *PerconaXtraDBCluster) GetStorageSize() string {
+	if cr.Spec.PXC == nil || cr.Spec.PXC.VolumeSpec == nil || cr.Spec.PXC.VolumeSpec.PersistentVolumeClaim == nil {
+		return ""
+	}
+	size, ok := cr.Spec.PXC.VolumeSpec.PersistentVolumeClaim.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return ""
+	}
+
+	return size.String()
+}
+
+func (cr *PerconaXtraDBCluster) IsPaused() bool {
+	return cr.Spec.Pause
+}
+
+func (cr *PerconaXtraDBCluster) GetCreationTime() time.Time {
+	return cr.ObjectMeta.CreationTimestamp.Time
+}
+
 func (cr *PerconaXtraDBCluster) GetStatusHost() string {
 	return cr.Status.Host
 }
@@ -147,7 +224,7 @@ func (cr *PerconaXtraDBCluster) SetDefaults() error {
 	pmm := v1.PMMSpec{
 		Enabled:    false,
 		ServerHost: "monitoring-service",
-		Image:      "percona/percona-xtradb-cluster-operator:1.1.0-pmm",
+		Image:      pmmImage,
 	}
 	cr.Spec.PMM = &pmm
 
@@ -156,3 +233,144 @@ func (cr *PerconaXtraDBCluster) SetDefaults() error {
 	}
 	return nil
 }
+
+func (cr *PerconaXtraDBCluster) GetBackupSchedules() []dbaas.BackupSchedule {
+	if cr.Spec.Backup == nil {
+		return nil
+	}
+	list := make([]dbaas.BackupSchedule, 0, len(cr.Spec.Backup.Schedule))
+	for _, s := range cr.Spec.Backup.Schedule {
+		list = append(list, dbaas.BackupSchedule{
+			Name:     s.Name,
+			Schedule: s.Schedule,
+			Storage:  s.StorageName,
+			Keep:     s.Keep,
+		})
+	}
+
+	return list
+}
+
+func (cr *PerconaXtraDBCluster) SetBackupSchedules(schedules []dbaas.BackupSchedule) error {
+	if cr.Spec.Backup == nil {
+		cr.Spec.Backup = &v1.PXCScheduledBackup{}
+	}
+	cr.Spec.Backup.Schedule = make([]v1.PXCScheduledBackupSchedule, 0, len(schedules))
+	for _, s := range schedules {
+		cr.Spec.Backup.Schedule = append(cr.Spec.Backup.Schedule, v1.PXCScheduledBackupSchedule{
+			Name:        s.Name,
+			Schedule:    s.Schedule,
+			StorageName: s.Storage,
+			Keep:        s.Keep,
+		})
+	}
+
+	return nil
+}
+
+func (cr *PerconaXtraDBCluster) GetBackupStorages() []dbaas.BackupStorage {
+	if cr.Spec.Backup == nil {
+		return nil
+	}
+	names := make([]string, 0, len(cr.Spec.Backup.Storages))
+	for name := range cr.Spec.Backup.Storages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]dbaas.BackupStorage, 0, len(names))
+	for _, name := range names {
+		s := cr.Spec.Backup.Storages[name]
+		if s == nil {
+			continue
+		}
+		storage := dbaas.BackupStorage{
+			Name:              name,
+			Type:              string(s.Type),
+			Bucket:            s.S3.Bucket,
+			Region:            s.S3.Region,
+			EndpointURL:       s.S3.EndpointURL,
+			CredentialsSecret: s.S3.CredentialsSecret,
+		}
+		if s.Volume != nil && s.Volume.PersistentVolumeClaim != nil {
+			pvc := s.Volume.PersistentVolumeClaim
+			if size, ok := pvc.Resources.Requests[corev1.ResourceStorage]; ok {
+				storage.Size = size.String()
+			}
+			if pvc.StorageClassName != nil {
+				storage.StorageClass = *pvc.StorageClassName
+			}
+		}
+		list = append(list, storage)
+	}
+
+	return list
+}
+
+func (cr *PerconaXtraDBCluster) SetBackupStorage(storage dbaas.BackupStorage) error {
+	spec := &v1.BackupStorageSpec{
+		Type: v1.BackupStorageType(storage.Type),
+	}
+	switch storage.Type {
+	case "s3":
+		spec.S3 = v1.BackupStorageS3Spec{
+			Bucket:            storage.Bucket,
+			Region:            storage.Region,
+			EndpointURL:       storage.EndpointURL,
+			CredentialsSecret: storage.CredentialsSecret,
+		}
+	case "filesystem":
+		size, err := resource.ParseQuantity(storage.Size)
+		if err != nil {
+			return errors.Wrapf(err, "parse size %s", storage.Size)
+		}
+		pvc := &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		}
+		if len(storage.StorageClass) > 0 {
+			class := storage.StorageClass
+			pvc.StorageClassName = &class
+		}
+		spec.Volume = &v1.VolumeSpec{PersistentVolumeClaim: pvc}
+	default:
+		return errors.Errorf("unknown backup storage type %s", storage.Type)
+	}
+
+	if cr.Spec.Backup == nil {
+		cr.Spec.Backup = &v1.PXCScheduledBackup{}
+	}
+	if cr.Spec.Backup.Storages == nil {
+		cr.Spec.Backup.Storages = make(map[string]*v1.BackupStorageSpec)
+	}
+	cr.Spec.Backup.Storages[storage.Name] = spec
+
+	return nil
+}
+
+func (cr *PerconaXtraDBCluster) GetPMM() (enabled bool, serverHost, serverUser string) {
+	if cr.Spec.PMM == nil {
+		return false, "", ""
+	}
+
+	return cr.Spec.PMM.Enabled, cr.Spec.PMM.ServerHost, cr.Spec.PMM.ServerUser
+}
+
+// SetPMM turns the PMM client on or off, empty serverHost and serverUser keep the current values
+func (cr *PerconaXtraDBCluster) SetPMM(enabled bool, serverHost, serverUser string) {
+	if cr.Spec.PMM == nil {
+		cr.Spec.PMM = &v1.PMMSpec{}
+	}
+	if len(cr.Spec.PMM.Image) == 0 {
+		cr.Spec.PMM.Image = pmmImage
+	}
+	cr.Spec.PMM.Enabled = enabled
+	if len(serverHost) > 0 {
+		cr.Spec.PMM.ServerHost = serverHost
+	}
+	if len(serverUser) > 0 {
+		cr.Spec.PMM.ServerUser = serverUser
+	}
+}
@@ -0,0 +1,46 @@
+package dbaas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// The errors of the engines are wrapped with the details, so they are checked with errors.Is:
+//
+//	if errors.Is(err, dbaas.ErrNotFound) {
+//		...
+//	}
+var (
+	// ErrNotFound is returned when the database or the object the operation needs doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when the database or the object to create exists
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrUnsupportedVersion is returned for the operator version the engine doesn't know
+	ErrUnsupportedVersion = errors.New("unsupported version")
+	// ErrInsufficientResources is returned when the nodes have not enough CPU or memory for the pods
+	ErrInsufficientResources = errors.New("insufficient resources")
+	// ErrPermissionDenied is returned when the user isn't allowed to manage the objects
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrDataLoss is returned when the restore would lose the changes made after the backup
+	// and the loss isn't accepted
+	ErrDataLoss = errors.New("data loss")
+)
+
+// ErrInvalidOption is returned for the unknown option or the invalid value of it, it is checked with errors.As
+type ErrInvalidOption struct {
+	// Path is the path of the option, e.g. "spec.pxc.size"
+	Path string
+	Err  error
+}
+
+func (e ErrInvalidOption) Error() string {
+	if e.Err == nil {
+		return "invalid option " + e.Path
+	}
+
+	return "invalid option " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the reason the option is invalid
+func (e ErrInvalidOption) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,42 @@
+package dbaas
+
+// Health check statuses from the best to the worst
+const (
+	HealthOK      = "ok"
+	HealthWarning = "warning"
+	HealthFailed  = "failed"
+)
+
+// HealthCheck is the result of the check of the cluster object
+type HealthCheck struct {
+	Component string `json:"component"`
+	Object    string `json:"object"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
+
+// Health is the health report of the cluster
+type Health struct {
+	Cluster string `json:"cluster"`
+	// Status is the overall verdict, the worst status of the checks
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+var healthRank = map[string]int{
+	HealthOK:      0,
+	HealthWarning: 1,
+	HealthFailed:  2,
+}
+
+// Verdict returns the worst status of the checks
+func Verdict(checks []HealthCheck) string {
+	status := HealthOK
+	for _, c := range checks {
+		if healthRank[c.Status] > healthRank[status] {
+			status = c.Status
+		}
+	}
+
+	return status
+}
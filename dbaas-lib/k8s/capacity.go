@@ -0,0 +1,146 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// ErrInsufficientResources means that the cluster nodes can't fit the requested pods
+var ErrInsufficientResources = dbaas.ErrInsufficientResources
+
+type nodeResources struct {
+	cpu    resource.Quantity
+	memory resource.Quantity
+}
+
+// CheckCapacity checks that the schedulable nodes have enough allocatable resources left
+// for the given number of new pods with the given CPU and memory requests.
+// The check is skipped if the user isn't allowed to list nodes
+func (p Cmd) CheckCapacity(pods int, cpu, memory string) error {
+	if pods <= 0 || (len(cpu) == 0 && len(memory) == 0) {
+		return nil
+	}
+	var reqCPU, reqMemory resource.Quantity
+	var err error
+	if len(cpu) > 0 {
+		reqCPU, err = resource.ParseQuantity(cpu)
+		if err != nil {
+			return errors.Wrapf(err, "parse cpu request %s", cpu)
+		}
+	}
+	if len(memory) > 0 {
+		reqMemory, err = resource.ParseQuantity(memory)
+		if err != nil {
+			return errors.Wrapf(err, "parse memory request %s", memory)
+		}
+	}
+
+	free, err := p.freeResources()
+	if err != nil {
+		if errors.Is(err, dbaas.ErrPermissionDenied) {
+			return nil
+		}
+		return errors.Wrap(err, "get free resources")
+	}
+
+	for i := 0; i < pods; i++ {
+		fit := false
+		for _, n := range free {
+			if n.cpu.Cmp(reqCPU) >= 0 && n.memory.Cmp(reqMemory) >= 0 {
+				n.cpu.Sub(reqCPU)
+				n.memory.Sub(reqMemory)
+				fit = true
+				break
+			}
+		}
+		if !fit {
+			return errors.Wrapf(ErrInsufficientResources, "nodes can fit only %d of %d new pods requesting %s CPU and %s memory", i, pods, reqCPU.String(), reqMemory.String())
+		}
+	}
+
+	return nil
+}
+
+// freeResources returns allocatable resources of the ready schedulable nodes minus requests of the pods running on them.
+// The nodes with the most free memory go first
+func (p Cmd) freeResources() ([]*nodeResources, error) {
+	data, err := p.runCmd(p.execCommand, "get", "nodes", "-o", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "get nodes")
+	}
+	var nodes corev1.NodeList
+	err = json.Unmarshal(data, &nodes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal nodes")
+	}
+
+	free := make(map[string]*nodeResources)
+	for _, n := range nodes.Items {
+		if n.Spec.Unschedulable {
+			continue
+		}
+		ready := false
+		for _, c := range n.Status.Conditions {
+			if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue {
+				ready = true
+			}
+		}
+		if !ready {
+			continue
+		}
+		free[n.Name] = &nodeResources{
+			cpu:    n.Status.Allocatable.Cpu().DeepCopy(),
+			memory: n.Status.Allocatable.Memory().DeepCopy(),
+		}
+	}
+
+	data, err = p.runCmd(p.execCommand, "get", "pods", "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "get pods")
+	}
+	var pods corev1.PodList
+	err = json.Unmarshal(data, &pods)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal pods")
+	}
+	for _, pod := range pods.Items {
+		n, ok := free[pod.Spec.NodeName]
+		if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			n.cpu.Sub(*c.Resources.Requests.Cpu())
+			n.memory.Sub(*c.Resources.Requests.Memory())
+		}
+	}
+
+	list := make([]*nodeResources, 0, len(free))
+	for _, n := range free {
+		list = append(list, n)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].memory.Cmp(list[j].memory) > 0
+	})
+
+	return list, nil
+}
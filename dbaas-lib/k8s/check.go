@@ -84,3 +84,14 @@ func getOperatorImageVersion(image string) (string, error) {
 
 	return imageArr[1], nil
 }
+
+// ImageVersion returns the database version from the image tag. The operator images have the version
+// after the marker, e.g. "8.0" for "percona/percona-xtradb-cluster-operator:1.4.0-pxc8.0" and "-pxc"
+func ImageVersion(image, marker string) string {
+	tag := image[strings.LastIndex(image, ":")+1:]
+	if i := strings.Index(tag, marker); i >= 0 {
+		return tag[i+len(marker):]
+	}
+
+	return tag
+}
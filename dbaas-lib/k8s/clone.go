@@ -0,0 +1,344 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+const (
+	snapshotGroup                      = "snapshot.storage.k8s.io"
+	snapshotAPIVersion                 = snapshotGroup + "/v1beta1"
+	defaultSnapshotClassAnnotation     = "snapshot.storage.kubernetes.io/is-default-class"
+	lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// ErrSnapshotsNotSupported is returned when the volumes can't be cloned with volume snapshots
+var ErrSnapshotsNotSupported = errors.New("volume snapshots aren't supported")
+
+type snapshotClassList struct {
+	Items []struct {
+		metav1.ObjectMeta `json:"metadata"`
+		Driver            string `json:"driver"`
+	} `json:"items"`
+}
+
+// snapshotClass returns the volume snapshot class of the CSI driver provisioning the claim volume
+func (p Cmd) snapshotClass(pvc corev1.PersistentVolumeClaim) (string, error) {
+	sc, err := p.storageClass(pvc)
+	if err != nil {
+		return "", errors.Wrap(err, "get storage class")
+	}
+	data, err := p.GetObjects("volumesnapshotclass")
+	if err == ErrNotFound {
+		return "", ErrSnapshotsNotSupported
+	} else if err != nil {
+		return "", errors.Wrap(err, "get volume snapshot classes")
+	}
+	var list snapshotClassList
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return "", errors.Wrap(err, "unmarshal volume snapshot classes")
+	}
+
+	class := ""
+	for _, c := range list.Items {
+		if c.Driver != sc.Provisioner {
+			continue
+		}
+		if len(class) == 0 || c.Annotations[defaultSnapshotClassAnnotation] == "true" {
+			class = c.Name
+		}
+	}
+	if len(class) == 0 {
+		return "", ErrSnapshotsNotSupported
+	}
+
+	return class, nil
+}
+
+// CloneVolumes takes snapshots of the claims matching the labels and creates the claims for the new
+// cluster from them. The claims are named after the new cluster, so its statefulsets pick them up.
+// It returns ErrSnapshotsNotSupported if any of the volumes can't be snapshotted, the names of the
+// snapshots otherwise. The snapshots have to be kept until the new volumes are provisioned
+func (p Cmd) CloneVolumes(labels, srcName, dstName string, timeout time.Duration) ([]string, error) {
+	pvcs, err := p.getPVCs(labels)
+	if err != nil {
+		return nil, err
+	}
+	if len(pvcs) == 0 {
+		return nil, errors.Errorf("no pvc found by labels %s", labels)
+	}
+
+	classes := make(map[string]string, len(pvcs))
+	for _, pvc := range pvcs {
+		classes[pvc.Name], err = p.snapshotClass(pvc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	suffix := GenRandString(5)
+	var snapshots []string
+	for _, pvc := range pvcs {
+		name := pvc.Name + "-clone-" + suffix
+		snapshot := map[string]interface{}{
+			"apiVersion": snapshotAPIVersion,
+			"kind":       "VolumeSnapshot",
+			"metadata": map[string]interface{}{
+				"name":   name,
+				"labels": map[string]string{instanceLabel: dstName},
+			},
+			"spec": map[string]interface{}{
+				"volumeSnapshotClassName": classes[pvc.Name],
+				"source": map[string]string{
+					"persistentVolumeClaimName": pvc.Name,
+				},
+			},
+		}
+		obj, err := json.Marshal(snapshot)
+		if err != nil {
+			return snapshots, errors.Wrap(err, "marshal volume snapshot")
+		}
+		err = p.apply(string(obj))
+		if err != nil {
+			return snapshots, errors.Wrapf(err, "create snapshot of %s", pvc.Name)
+		}
+		snapshots = append(snapshots, name)
+	}
+
+	err = p.waitSnapshotsReady(snapshots, timeout)
+	if err != nil {
+		return snapshots, err
+	}
+
+	group := snapshotGroup
+	for i, pvc := range pvcs {
+		newPVC := corev1.PersistentVolumeClaim{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "PersistentVolumeClaim",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      pvc.Spec.AccessModes,
+				StorageClassName: pvc.Spec.StorageClassName,
+				Resources:        pvc.Spec.Resources,
+				VolumeMode:       pvc.Spec.VolumeMode,
+				DataSource: &corev1.TypedLocalObjectReference{
+					APIGroup: &group,
+					Kind:     "VolumeSnapshot",
+					Name:     snapshots[i],
+				},
+			},
+		}
+		newPVC.Name, err = renamePVC(pvc.Name, srcName, dstName)
+		if err != nil {
+			return snapshots, err
+		}
+		newPVC.Labels = make(map[string]string, len(pvc.Labels))
+		for k, v := range pvc.Labels {
+			newPVC.Labels[k] = v
+		}
+		newPVC.Labels[instanceLabel] = dstName
+		obj, err := json.Marshal(newPVC)
+		if err != nil {
+			return snapshots, errors.Wrap(err, "marshal pvc")
+		}
+		err = p.apply(string(obj))
+		if err != nil {
+			return snapshots, errors.Wrapf(err, "create pvc %s", newPVC.Name)
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (p Cmd) waitSnapshotsReady(names []string, timeout time.Duration) error {
+	pending := make(map[string]string, len(names))
+	for _, n := range names {
+		pending[n] = "not ready"
+	}
+	deadline := time.Now().Add(timeout)
+	for len(pending) > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Second)
+
+		for name := range pending {
+			data, err := p.GetObject("volumesnapshot", name)
+			if err != nil {
+				continue
+			}
+			var snapshot struct {
+				Status struct {
+					ReadyToUse *bool `json:"readyToUse"`
+					Error      *struct {
+						Message string `json:"message"`
+					} `json:"error"`
+				} `json:"status"`
+			}
+			err = json.Unmarshal(data, &snapshot)
+			if err != nil {
+				return errors.Wrap(err, "unmarshal volume snapshot")
+			}
+			if snapshot.Status.ReadyToUse != nil && *snapshot.Status.ReadyToUse {
+				delete(pending, name)
+				continue
+			}
+			if snapshot.Status.Error != nil {
+				return errors.Errorf("snapshot %s failed: %s", name, snapshot.Status.Error.Message)
+			}
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var st []string
+	for name := range pending {
+		st = append(st, name)
+	}
+	sort.Strings(st)
+	return errors.Errorf("snapshots aren't ready after %s: %s", timeout, strings.Join(st, ", "))
+}
+
+// DeleteSnapshots deletes the volume snapshots
+func (p Cmd) DeleteSnapshots(names []string) error {
+	for _, name := range names {
+		err := p.DeleteObject("volumesnapshot", name)
+		if err != nil {
+			return errors.Wrapf(err, "delete snapshot %s", name)
+		}
+	}
+
+	return nil
+}
+
+// CleanCR removes the metadata, the dbaas annotations and the status of the existing object
+// from the custom resource, so it can be used to create the new object
+func CleanCR(cr string) (string, error) {
+	obj := make(map[string]interface{})
+	err := json.Unmarshal([]byte(cr), &obj)
+	if err != nil {
+		return "", errors.Wrap(err, "unmarshal cr")
+	}
+	delete(obj, "status")
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, k := range []string{"uid", "resourceVersion", "creationTimestamp", "generation", "selfLink", "managedFields", "ownerReferences", "finalizers"} {
+			delete(meta, k)
+		}
+		if annotations, ok := meta["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedConfigurationAnnotation)
+			// the annotations of the dbaas tools, e.g. the broker instance ID, belong to the existing object
+			for k := range annotations {
+				if strings.HasPrefix(k, dbaas.AnnotationPrefix) {
+					delete(annotations, k)
+				}
+			}
+		}
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal cr")
+	}
+
+	return string(b), nil
+}
+
+// BackupStorages returns the sorted names of the backup storages defined in the cluster custom resource
+func BackupStorages(cr []byte) ([]string, error) {
+	var obj struct {
+		Spec struct {
+			Backup *struct {
+				Storages map[string]json.RawMessage `json:"storages"`
+			} `json:"backup"`
+		} `json:"spec"`
+	}
+	err := json.Unmarshal(cr, &obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal cluster object")
+	}
+	if obj.Spec.Backup == nil {
+		return nil, nil
+	}
+
+	var names []string
+	for name := range obj.Spec.Backup.Storages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// CreateObject creates the object from its manifest marshalled to JSON
+func (p Cmd) CreateObject(obj interface{}) error {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return errors.Wrap(err, "marshal object")
+	}
+
+	return p.apply(string(b))
+}
+
+// WaitState waits until the ".status.state" of the object is one of the ready states.
+// It fails as soon as the object gets into one of the failed states
+func (p Cmd) WaitState(typ, name string, ready, failed []string, timeout time.Duration) error {
+	state := ""
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(5 * time.Second)
+
+		data, err := p.GetObjectsElement(typ, name, ".status.state")
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "get %s state", name)
+		}
+		state = strings.TrimSpace(string(data))
+		for _, s := range ready {
+			if strings.EqualFold(state, s) {
+				return nil
+			}
+		}
+		for _, s := range failed {
+			if strings.EqualFold(state, s) {
+				return p.stateError(typ, name, state)
+			}
+		}
+	}
+
+	return errors.Errorf("%s isn't ready after %s, the last state is %q", name, timeout, state)
+}
+
+func (p Cmd) stateError(typ, name, state string) error {
+	msg, err := p.GetObjectsElement(typ, name, ".status.comments")
+	if err != nil || len(strings.TrimSpace(string(msg))) == 0 {
+		msg, _ = p.GetObjectsElement(typ, name, ".status.error")
+	}
+	if len(strings.TrimSpace(string(msg))) == 0 {
+		return errors.Errorf("%s is in %s state", name, state)
+	}
+
+	return errors.Errorf("%s is in %s state: %s", name, state, strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCleanCR(t *testing.T) {
+	cr, err := CleanCR(`{
+		"metadata": {
+			"name": "src",
+			"uid": "42",
+			"resourceVersion": "7",
+			"labels": {"team": "payments"},
+			"annotations": {
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"dbaas.percona.com/broker-instance": "instance-id",
+				"example.com/note": "kept"
+			}
+		},
+		"spec": {"pxc": {"size": 3}},
+		"status": {"state": "ready"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var obj struct {
+		Metadata struct {
+			UID         string            `json:"uid"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec   map[string]interface{} `json:"spec"`
+		Status map[string]interface{} `json:"status"`
+	}
+	err = json.Unmarshal([]byte(cr), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obj.Metadata.UID) > 0 || obj.Status != nil {
+		t.Errorf("uid or status is kept: %s", cr)
+	}
+	if len(obj.Metadata.Annotations) != 1 || obj.Metadata.Annotations["example.com/note"] != "kept" {
+		t.Errorf("unexpected annotations: %v", obj.Metadata.Annotations)
+	}
+	if obj.Spec["pxc"] == nil {
+		t.Errorf("spec is lost: %s", cr)
+	}
+}
@@ -21,22 +21,40 @@ import (
 	"math/rand"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 )
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
+// The errors are the ones of the dbaas package or are checked as them with errors.Is
 var (
-	ErrOutOfMemory = errors.New("out of memory")
-	ErrNotFound    = errors.New("not found")
+	ErrOutOfMemory error = &kindError{msg: "out of memory", kind: dbaas.ErrInsufficientResources}
+	ErrNotFound          = dbaas.ErrNotFound
 )
 
+// kindError has its own message and is checked with errors.Is as the error of the kind
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
 type PlatformType string
 
 const (
@@ -66,8 +84,47 @@ type Pods struct {
 	Items []corev1.Pod `json:"items"`
 }
 
+// Is tells that the command failed because the object doesn't exist or the user isn't allowed to access it
+func (e ErrCmdRun) Is(target error) bool {
+	switch target {
+	case dbaas.ErrNotFound:
+		return bytes.Contains(e.output, []byte("(NotFound)"))
+	case dbaas.ErrPermissionDenied:
+		return bytes.Contains(e.output, []byte("(Forbidden)")) || bytes.Contains(e.output, []byte(" is forbidden:"))
+	}
+
+	return false
+}
+
 func (e ErrCmdRun) Error() string {
-	return fmt.Sprintf("failed to run `%s %s`, output: %s", e.cmd, strings.Join(e.args, " "), e.output)
+	return fmt.Sprintf("failed to run `%s %s`, output: %s", e.cmd, strings.Join(e.args, " "), redactOutput(e.args, e.output))
+}
+
+var secretDataRe = regexp.MustCompile(`"(data|stringData)"\s*:\s*\{[^}]*\}`)
+
+// redactOutput hides secrets data that can be a part of the command output.
+// Error messages are kept as callers rely on them
+func redactOutput(args []string, output []byte) []byte {
+	output = secretDataRe.ReplaceAll(output, []byte(`"$1": <redacted>`))
+
+	secretCmd := false
+	for _, a := range args {
+		if a == "secret" || a == "secrets" || strings.HasPrefix(a, "secret/") || strings.HasPrefix(a, "secrets/") {
+			secretCmd = true
+		}
+	}
+	if !secretCmd {
+		return output
+	}
+
+	lines := bytes.Split(output, []byte("\n"))
+	for i, l := range lines {
+		if len(bytes.TrimSpace(l)) > 0 && !bytes.HasPrefix(bytes.ToLower(l), []byte("error")) {
+			lines[i] = []byte("<redacted>")
+		}
+	}
+
+	return bytes.Join(lines, []byte("\n"))
 }
 
 func New(environment string) (*Cmd, error) {
@@ -124,7 +181,9 @@ func (p Cmd) runNTimes(n int, cmd string, args ...string) (o []byte, err error)
 		if len(p.environment) > 0 {
 			cli.Env = append(cli.Env, "KUBECONFIG="+p.environment)
 		}
+		started := time.Now()
 		o, err = cli.CombinedOutput()
+		trace(cmd, args, o, started, err)
 		if err != nil {
 			if strings.Contains(string(o), "Unable to connect to the server") && i < n {
 				continue
@@ -137,7 +196,8 @@ func (p Cmd) runNTimes(n int, cmd string, args ...string) (o []byte, err error)
 }
 
 func (p Cmd) readOperatorLogs(operatorName string) ([]byte, error) {
-	return p.runCmd(p.execCommand, "logs", "-l", "name="+operatorName)
+	// logs of the pods selected by the labels are limited to the last 10 lines by default
+	return p.kubectl("logs", "-l", "name="+operatorName, "--tail=-1")
 }
 
 func (p Cmd) GetObjectsElement(typ, name, jsonPath string) ([]byte, error) {
@@ -275,15 +335,6 @@ func (p Cmd) Instances(typ string) ([]string, error) {
 	return strings.Split(strings.TrimSpace(string(out)), "\n"), nil
 }
 
-func (p Cmd) GetServiceBrokerInstances(typ string) ([]byte, error) {
-	out, err := p.runCmd(p.execCommand, "get", typ, "-o", "jsonpath='{.items..metadata.annotations.broker-instance}'")
-	if err != nil && !strings.Contains(err.Error(), "NotFound") {
-		return nil, errors.Wrapf(err, "get objects: %s", out)
-	}
-
-	return out, nil
-}
-
 const genSymbols = "abcdefghijklmnopqrstuvwxyz1234567890"
 
 // GenRandString generates a k8s-name legitimate string of given length
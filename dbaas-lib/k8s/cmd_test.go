@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+func TestErrCmdRunRedactsSecrets(t *testing.T) {
+	const secret = "cm9vdHBhc3N3b3Jk"
+	cases := []ErrCmdRun{
+		{cmd: "kubectl", args: []string{"get", "secrets/my-secrets", "-o", "json"}, output: []byte(`{"data": {"root": "` + secret + `"}}`)},
+		{cmd: "kubectl", args: []string{"get", "secret", "my-secrets"}, output: []byte(secret)},
+		{cmd: "kubectl", args: []string{"apply", "-f", "/tmp/obj"}, output: []byte(`{"kind": "Secret", "data": {"root": "` + secret + `"}}`)},
+	}
+	for _, c := range cases {
+		if strings.Contains(c.Error(), secret) {
+			t.Errorf("error of `%s` contains the secret: %s", strings.Join(c.args, " "), c.Error())
+		}
+	}
+
+	notFound := ErrCmdRun{cmd: "kubectl", args: []string{"get", "secret", "my-secrets", "-o", "name"}, output: []byte(`Error from server (NotFound): secrets "my-secrets" not found`)}
+	if !strings.Contains(notFound.Error(), "NotFound") {
+		t.Errorf("error message should be kept: %s", notFound.Error())
+	}
+}
+
+func TestErrCmdRunIs(t *testing.T) {
+	notFound := ErrCmdRun{cmd: "kubectl", args: []string{"get", "pxc", "orders"}, output: []byte(`Error from server (NotFound): perconaxtradbclusters.pxc.percona.com "orders" not found`)}
+	if !errors.Is(errors.Wrap(notFound, "get cluster object"), dbaas.ErrNotFound) {
+		t.Error("NotFound output isn't dbaas.ErrNotFound")
+	}
+	forbidden := ErrCmdRun{cmd: "kubectl", args: []string{"get", "nodes"}, output: []byte(`Error from server (Forbidden): nodes is forbidden: User "dev" cannot list resource "nodes"`)}
+	if !errors.Is(forbidden, dbaas.ErrPermissionDenied) || errors.Is(forbidden, dbaas.ErrNotFound) {
+		t.Error("Forbidden output should be dbaas.ErrPermissionDenied only")
+	}
+	if !errors.Is(errors.Wrap(ErrAlreadyExists{Typ: "pxc", Cluster: "orders"}, "create cluster"), dbaas.ErrAlreadyExists) {
+		t.Error("ErrAlreadyExists isn't dbaas.ErrAlreadyExists")
+	}
+	if !errors.Is(ErrOutOfMemory, dbaas.ErrInsufficientResources) || ErrOutOfMemory.Error() != "out of memory" {
+		t.Error("ErrOutOfMemory should be dbaas.ErrInsufficientResources with its own message")
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 )
 
 func init() {
@@ -73,6 +75,11 @@ func (e ErrAlreadyExists) Error() string {
 	return fmt.Sprintf("cluster %s/%s already exists", e.Typ, e.Cluster)
 }
 
+// Is makes the error checked as dbaas.ErrAlreadyExists
+func (e ErrAlreadyExists) Is(target error) bool {
+	return target == dbaas.ErrAlreadyExists
+}
+
 const osRightsMsg = `Not enough rights to pre-setup cluster.
 Try to login under the privileged user run one of the commands listed below and then call "percona-dbaas pxc create ..." again
 
@@ -95,7 +102,7 @@ func (p Cmd) CreateCluster(typ, operatorVersion, clusterName, cr string, bundle
 	if err != nil {
 		if strings.Contains(err.Error(), "error: the server doesn't have a resource type") ||
 			strings.Contains(err.Error(), "Error from server (Forbidden):") {
-			return errors.Errorf(osRightsMsg, p.execCommand, p.osUser(), p.execCommand, osAdminBundle(bundle), p.osUser())
+			return errors.WithMessagef(err, osRightsMsg, p.execCommand, p.osUser(), p.execCommand, osAdminBundle(bundle), p.osUser())
 		}
 		return errors.Wrap(err, "check if cluster exists")
 	}
@@ -113,6 +120,10 @@ func (p Cmd) CreateCluster(typ, operatorVersion, clusterName, cr string, bundle
 
 // CreateSecret creates k8s secret object with the given name and data
 func (p Cmd) CreateSecret(name string, data map[string][]byte) error {
+	return p.createSecret(name, corev1.SecretTypeOpaque, data)
+}
+
+func (p Cmd) createSecret(name string, typ corev1.SecretType, data map[string][]byte) error {
 	s := corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -122,7 +133,7 @@ func (p Cmd) CreateSecret(name string, data map[string][]byte) error {
 			Name: name,
 		},
 		Data: data,
-		Type: corev1.SecretTypeOpaque,
+		Type: typ,
 	}
 
 	sj, err := json.Marshal(s)
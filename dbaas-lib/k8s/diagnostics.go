@@ -0,0 +1,173 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Diagnostic is the piece of the diagnostics bundle. Err is set if it couldn't be collected
+type Diagnostic struct {
+	Name string
+	Data []byte
+	Err  error
+}
+
+// CollectDiagnostics gathers the state of the cluster of typ for the support case: the custom resource,
+// the operator deployment and logs, pods, statefulsets, volume claims, events, CRD versions and the names
+// and keys of the cluster secrets. The secrets values aren't collected. Failures to collect the single
+// piece are kept in its Err, so the rest of the bundle is still useful
+func (p Cmd) CollectDiagnostics(typ, operatorName, appName string) ([]Diagnostic, error) {
+	ext, err := p.IsObjExists(typ, appName)
+	if err != nil {
+		return nil, errors.Wrap(err, "check if cluster exists")
+	}
+	if !ext {
+		return nil, errors.Wrapf(dbaas.ErrNotFound, "cluster %s/%s", typ, appName)
+	}
+
+	labels := instanceLabel + "=" + appName
+	var list []Diagnostic
+	add := func(name string, data []byte, err error) {
+		list = append(list, Diagnostic{Name: name, Data: redactOutput(nil, data), Err: err})
+	}
+
+	data, err := p.kubectl("get", typ+"/"+appName, "-o", "yaml")
+	add("cluster.yaml", data, err)
+	data, err = p.kubectl("get", "deployment/"+operatorName, "-o", "yaml")
+	add("operator-deployment.yaml", data, err)
+	data, err = p.readOperatorLogs(operatorName)
+	add("operator.log", data, err)
+	data, err = p.kubectl("get", "statefulsets", "-l", labels, "-o", "yaml")
+	add("statefulsets.yaml", data, err)
+	data, err = p.kubectl("describe", "pods", "-l", labels)
+	add("pods.txt", data, err)
+	data, err = p.kubectl("describe", "pvc", "-l", labels)
+	add("pvc.txt", data, err)
+	data, err = p.eventsTimeline(appName)
+	add("events.txt", data, err)
+	data, err = p.crdVersions()
+	add("crds.txt", data, err)
+	data, err = p.secretKeys(appName)
+	add("secrets.txt", data, err)
+
+	return list, nil
+}
+
+// kubectl runs the command in the namespace of the cluster
+func (p Cmd) kubectl(args ...string) ([]byte, error) {
+	if len(p.Namespace) > 0 {
+		args = append(args, "-n", p.Namespace)
+	}
+
+	return p.runCmd(p.execCommand, args...)
+}
+
+// eventsTimeline returns the events of the cluster objects as the lines of text
+func (p Cmd) eventsTimeline(appName string) ([]byte, error) {
+	events, err := p.ClusterEvents(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for _, e := range events {
+		fmt.Fprintf(&b, "%s\t%s\t%s\tx%d\t%s\t%s\n", e.Last.UTC().Format(time.RFC3339), e.Type, e.Reason, e.Count, e.Object, e.Message)
+	}
+
+	return b.Bytes(), nil
+}
+
+// crdVersions returns the served versions of the Percona custom resource definitions
+func (p Cmd) crdVersions() ([]byte, error) {
+	data, err := p.runCmd(p.execCommand, "get", "crd", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var crds struct {
+		Items []struct {
+			metav1.ObjectMeta `json:"metadata"`
+			Spec              struct {
+				Version  string `json:"version"`
+				Versions []struct {
+					Name   string `json:"name"`
+					Served bool   `json:"served"`
+				} `json:"versions"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	err = json.Unmarshal(data, &crds)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal crds")
+	}
+
+	var b bytes.Buffer
+	for _, crd := range crds.Items {
+		if !strings.HasSuffix(crd.Name, ".percona.com") {
+			continue
+		}
+		var versions []string
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				versions = append(versions, v.Name)
+			}
+		}
+		if len(versions) == 0 {
+			versions = []string{crd.Spec.Version}
+		}
+		fmt.Fprintf(&b, "%s\t%s\n", crd.Name, strings.Join(versions, ","))
+	}
+
+	return b.Bytes(), nil
+}
+
+// secretKeys returns the names, types and keys of the secrets named after the cluster, the values are left out
+func (p Cmd) secretKeys(appName string) ([]byte, error) {
+	data, err := p.kubectl("get", "secrets", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var secrets corev1.SecretList
+	err = json.Unmarshal(data, &secrets)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal secrets")
+	}
+
+	var b bytes.Buffer
+	for _, s := range secrets.Items {
+		if !strings.HasPrefix(s.Name, appName) {
+			continue
+		}
+		var keys []string
+		for k := range s.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(&b, "%s\t%s\t%s\n", s.Name, s.Type, strings.Join(keys, ","))
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,235 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Kinds of the problems preventing the cluster pods from running
+const (
+	ProblemScheduling = "scheduling"
+	ProblemImagePull  = "image-pull"
+	ProblemVolume     = "volume"
+)
+
+var problemHints = map[string]string{
+	ProblemScheduling: "the nodes don't have enough resources for the pod, add nodes or use a smaller preset or resources requests",
+	ProblemImagePull:  "the image can't be pulled, check the image name and the access to the registry",
+	ProblemVolume:     "the volume can't be provisioned or attached, check the storage class and its provisioner",
+}
+
+// ErrClusterProblem is the problem preventing the cluster pods from running
+type ErrClusterProblem struct {
+	Kind    string
+	Object  string
+	Message string
+}
+
+func (e ErrClusterProblem) Error() string {
+	return fmt.Sprintf("%s problem with %s: %s", e.Kind, e.Object, e.Message)
+}
+
+// Is makes the scheduling problem checked as dbaas.ErrInsufficientResources
+func (e ErrClusterProblem) Is(target error) bool {
+	return e.Kind == ProblemScheduling && target == dbaas.ErrInsufficientResources
+}
+
+// Hint tells what can be done about the problem
+func (e ErrClusterProblem) Hint() string {
+	return problemHints[e.Kind]
+}
+
+// IsClusterProblem tells if the error is ErrClusterProblem or ErrOutOfMemory, so waiting
+// for the cluster won't help
+func IsClusterProblem(err error) bool {
+	if err == ErrOutOfMemory {
+		return true
+	}
+	_, ok := errors.Cause(err).(ErrClusterProblem)
+	return ok
+}
+
+// ProblemHint tells what can be done about the cluster problem, it is empty if the error isn't one
+func ProblemHint(err error) string {
+	if err == ErrOutOfMemory {
+		return problemHints[ProblemScheduling]
+	}
+	if p, ok := errors.Cause(err).(ErrClusterProblem); ok {
+		return p.Hint()
+	}
+
+	return ""
+}
+
+// PodsProblem returns the first problem of the pods: the failed scheduling, the image pull
+// or the volume binding. ErrOutOfMemory is returned if the nodes have not enough memory
+func PodsProblem(pods []corev1.Pod) error {
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodPending {
+			for _, c := range pod.Status.Conditions {
+				if c.Type != corev1.PodScheduled || c.Status != corev1.ConditionFalse {
+					continue
+				}
+				if strings.Contains(c.Message, "Insufficient memory") {
+					return ErrOutOfMemory
+				}
+				kind := ProblemScheduling
+				if strings.Contains(c.Message, "PersistentVolumeClaim") || strings.Contains(c.Message, "volume") {
+					kind = ProblemVolume
+				}
+				return ErrClusterProblem{Kind: kind, Object: "pod/" + pod.Name, Message: c.Message}
+			}
+		}
+		for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if cs.State.Waiting == nil {
+				continue
+			}
+			switch cs.State.Waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+				return ErrClusterProblem{Kind: ProblemImagePull, Object: "pod/" + pod.Name, Message: cs.State.Waiting.Message}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Event is the event of the cluster object
+type Event struct {
+	First   time.Time
+	Last    time.Time
+	Type    string
+	Reason  string
+	Object  string
+	Message string
+	Count   int32
+	// Problem is the kind of the problem the event reports, empty if it isn't one
+	Problem string
+}
+
+// Hint tells what can be done about the problem the event reports
+func (e Event) Hint() string {
+	return problemHints[e.Problem]
+}
+
+var problemReasons = map[string]string{
+	"FailedScheduling":   ProblemScheduling,
+	"ErrImagePull":       ProblemImagePull,
+	"ImagePullBackOff":   ProblemImagePull,
+	"InvalidImageName":   ProblemImagePull,
+	"FailedBinding":      ProblemVolume,
+	"ProvisioningFailed": ProblemVolume,
+	"FailedMount":        ProblemVolume,
+	"FailedAttachVolume": ProblemVolume,
+}
+
+// ClusterEvents returns the events of the custom resource, pods, volume claims, services and statefulsets
+// of the cluster sorted by the last occurrence. The same events of the object are merged
+func (p Cmd) ClusterEvents(appName string) ([]Event, error) {
+	data, err := p.kubectl("get", "events", "-o", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "get events")
+	}
+	var list corev1.EventList
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal events")
+	}
+
+	return clusterEvents(list.Items, appName), nil
+}
+
+func clusterEvents(items []corev1.Event, appName string) []Event {
+	merged := make(map[string]*Event)
+	var events []*Event
+	for _, e := range items {
+		obj := e.InvolvedObject
+		if !clusterObject(obj.Kind, obj.Name, appName) {
+			continue
+		}
+		first, last := e.FirstTimestamp.Time, e.LastTimestamp.Time
+		if last.IsZero() {
+			last = e.EventTime.Time
+		}
+		if first.IsZero() {
+			first = last
+		}
+		count := e.Count
+		if count == 0 {
+			count = 1
+		}
+
+		key := strings.Join([]string{obj.Kind, obj.Name, e.Reason, e.Message}, "\x00")
+		if ev, ok := merged[key]; ok {
+			ev.Count += count
+			if first.Before(ev.First) {
+				ev.First = first
+			}
+			if last.After(ev.Last) {
+				ev.Last = last
+			}
+			continue
+		}
+
+		problem := problemReasons[e.Reason]
+		if e.Reason == "Failed" && strings.Contains(e.Message, "pull") {
+			problem = ProblemImagePull
+		}
+		ev := &Event{
+			First:   first,
+			Last:    last,
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Object:  strings.ToLower(obj.Kind) + "/" + obj.Name,
+			Message: strings.TrimSpace(e.Message),
+			Count:   count,
+			Problem: problem,
+		}
+		merged[key] = ev
+		events = append(events, ev)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Last.Before(events[j].Last) })
+	list := make([]Event, 0, len(events))
+	for _, e := range events {
+		list = append(list, *e)
+	}
+
+	return list
+}
+
+// clusterObject tells if the object belongs to the cluster by its name. The objects are named
+// after the cluster, e.g. "cluster1-pxc-0", except volume claims, e.g. "datadir-cluster1-pxc-0"
+func clusterObject(kind, name, appName string) bool {
+	switch kind {
+	case "Pod", "Service", "StatefulSet":
+		return strings.HasPrefix(name, appName+"-")
+	case "PersistentVolumeClaim":
+		return strings.Contains(name, "-"+appName+"-")
+	}
+
+	// the custom resource of the cluster
+	return name == appName
+}
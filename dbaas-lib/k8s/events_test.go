@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterEvents(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	event := func(kind, name, reason, msg string, last time.Time) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+			Reason:         reason,
+			Message:        msg,
+			Count:          1,
+			FirstTimestamp: metav1.NewTime(last),
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+
+	events := clusterEvents([]corev1.Event{
+		event("Pod", "cluster1-pxc-0", "FailedScheduling", "0/3 nodes are available", now.Add(-time.Minute)),
+		event("PersistentVolumeClaim", "datadir-cluster1-pxc-0", "ProvisioningFailed", "no storage class", now.Add(-2*time.Minute)),
+		event("Pod", "cluster1-pxc-0", "FailedScheduling", "0/3 nodes are available", now),
+		event("Pod", "cluster10-pxc-0", "Pulled", "image pulled", now),
+		event("PerconaXtraDBCluster", "cluster1", "Created", "cluster created", now.Add(-3*time.Minute)),
+	}, "cluster1")
+
+	want := []struct {
+		object, problem string
+		count           int32
+	}{
+		{"perconaxtradbcluster/cluster1", "", 1},
+		{"persistentvolumeclaim/datadir-cluster1-pxc-0", ProblemVolume, 1},
+		{"pod/cluster1-pxc-0", ProblemScheduling, 2},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Object != w.object || e.Problem != w.problem || e.Count != w.count {
+			t.Errorf("event %d = %s %q x%d, want %s %q x%d", i, e.Object, e.Problem, e.Count, w.object, w.problem, w.count)
+		}
+	}
+	if !events[2].First.Equal(now.Add(-time.Minute)) || !events[2].Last.Equal(now) {
+		t.Errorf("merged event times = %s - %s", events[2].First, events[2].Last)
+	}
+}
+
+func TestPodsProblem(t *testing.T) {
+	pending := func(msg string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1-pxc-0"},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodPending,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Message: msg}},
+			},
+		}
+	}
+	pullErr := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1-pxc-1"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}},
+		},
+	}
+
+	if err := PodsProblem([]corev1.Pod{pending("0/3 nodes are available: 3 Insufficient memory.")}); err != ErrOutOfMemory {
+		t.Errorf("insufficient memory: got %v, want ErrOutOfMemory", err)
+	}
+	for _, c := range []struct {
+		pod  corev1.Pod
+		kind string
+	}{
+		{pending("0/3 nodes are available: 3 Insufficient cpu."), ProblemScheduling},
+		{pending(`pod has unbound immediate PersistentVolumeClaims`), ProblemVolume},
+		{pullErr, ProblemImagePull},
+	} {
+		err := PodsProblem([]corev1.Pod{c.pod})
+		p, ok := err.(ErrClusterProblem)
+		if !ok || p.Kind != c.kind {
+			t.Errorf("PodsProblem(%s) = %v, want %s problem", c.pod.Name, err, c.kind)
+		}
+		if !IsClusterProblem(err) || len(ProblemHint(err)) == 0 {
+			t.Errorf("%v should be the cluster problem with the hint", err)
+		}
+	}
+	if err := PodsProblem([]corev1.Pod{{Status: corev1.PodStatus{Phase: corev1.PodRunning}}}); err != nil {
+		t.Errorf("running pod: got %v", err)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Statuses of the health checks from the best to the worst
+const (
+	CheckOK      = "ok"
+	CheckWarning = "warning"
+	CheckFailed  = "failed"
+)
+
+const componentLabel = "app.kubernetes.io/component"
+
+// Check is the result of the health check of the cluster object
+type Check struct {
+	Component string
+	// Object is the checked object, e.g. "pod/cluster1-pxc-0"
+	Object  string
+	Status  string
+	Message string
+}
+
+// worseStatus returns the worse of the check statuses
+func worseStatus(a, b string) string {
+	rank := map[string]int{CheckOK: 0, CheckWarning: 1, CheckFailed: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+
+	return a
+}
+
+type pdbList struct {
+	Items []struct {
+		metav1.ObjectMeta `json:"metadata"`
+		Spec              struct {
+			Selector *metav1.LabelSelector `json:"selector"`
+		} `json:"spec"`
+		Status struct {
+			DisruptionsAllowed int32 `json:"disruptionsAllowed"`
+			CurrentHealthy     int32 `json:"currentHealthy"`
+			DesiredHealthy     int32 `json:"desiredHealthy"`
+			ExpectedPods       int32 `json:"expectedPods"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// CheckHealth checks the state of the cluster custom resource of typ and the phase, readiness and restarts
+// of the cluster pods, the binding of its volume claims and its pod disruption budgets
+func (p Cmd) CheckHealth(typ, appName string) ([]Check, error) {
+	cr, err := p.GetObject(typ, appName)
+	if err != nil {
+		return nil, errors.Wrap(err, "get cluster object")
+	}
+	checks := []Check{crCheck(typ, appName, cr)}
+
+	data, err := p.GetObjectByLables("pods", instanceLabel+"="+appName)
+	if err != nil {
+		return nil, errors.Wrap(err, "get pods")
+	}
+	var pods corev1.PodList
+	err = json.Unmarshal(data, &pods)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal pods")
+	}
+	for _, po := range pods.Items {
+		if len(po.Labels[componentLabel]) == 0 || po.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		checks = append(checks, podCheck(po))
+	}
+
+	pvcs, err := p.getPVCs(instanceLabel + "=" + appName)
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range pvcs {
+		c := Check{
+			Component: pvc.Labels[componentLabel],
+			Object:    "pvc/" + pvc.Name,
+			Status:    CheckOK,
+			Message:   string(pvc.Status.Phase),
+		}
+		if pvc.Status.Phase != corev1.ClaimBound {
+			c.Status = CheckFailed
+		}
+		checks = append(checks, c)
+	}
+
+	pdbChecks, err := p.pdbChecks(appName)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, pdbChecks...)
+
+	return checks, nil
+}
+
+// crCheck checks the state and the messages the operator put to the status of the custom resource
+func crCheck(typ, appName string, cr []byte) Check {
+	c := Check{
+		Component: "cluster",
+		Object:    typ + "/" + appName,
+		Status:    CheckOK,
+	}
+	var obj struct {
+		Status struct {
+			State   string          `json:"state"`
+			Message json.RawMessage `json:"message"`
+		} `json:"status"`
+	}
+	err := json.Unmarshal(cr, &obj)
+	if err != nil {
+		c.Status = CheckFailed
+		c.Message = "unable to read status: " + err.Error()
+		return c
+	}
+
+	// the operators have the message either as a string or as a list of strings
+	var messages []string
+	if json.Unmarshal(obj.Status.Message, &messages) != nil {
+		var msg string
+		if json.Unmarshal(obj.Status.Message, &msg) == nil && len(msg) > 0 {
+			messages = []string{msg}
+		}
+	}
+
+	state := strings.ToLower(obj.Status.State)
+	switch state {
+	case "ready":
+	case "error", "failed":
+		c.Status = CheckFailed
+	default:
+		c.Status = CheckWarning
+	}
+	if len(state) == 0 {
+		state = "unknown"
+	}
+	c.Message = strings.Join(append([]string{state}, messages...), "; ")
+
+	return c
+}
+
+// podCheck checks the phase and readiness of the pod and its containers restarts
+func podCheck(po corev1.Pod) Check {
+	c := Check{
+		Component: po.Labels[componentLabel],
+		Object:    "pod/" + po.Name,
+		Status:    CheckOK,
+	}
+	var problems []string
+	problem := func(status, msg string) {
+		c.Status = worseStatus(c.Status, status)
+		problems = append(problems, msg)
+	}
+
+	switch po.Status.Phase {
+	case corev1.PodRunning:
+		for _, cond := range po.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue {
+				problem(CheckFailed, "not ready")
+			}
+		}
+	case corev1.PodPending:
+		msg := "pending"
+		for _, cond := range po.Status.Conditions {
+			if cond.Status == corev1.ConditionFalse && len(cond.Message) > 0 {
+				msg += ": " + cond.Message
+			}
+		}
+		problem(CheckFailed, msg)
+	default:
+		problem(CheckFailed, strings.ToLower(string(po.Status.Phase)))
+	}
+
+	for _, cs := range po.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			problem(CheckFailed, cs.Name+" is in CrashLoopBackOff")
+		}
+		if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled" {
+			problem(CheckWarning, cs.Name+" was OOMKilled")
+		}
+		if cs.RestartCount > 0 {
+			problem(CheckWarning, fmt.Sprintf("%s restarted %d times", cs.Name, cs.RestartCount))
+		}
+	}
+	if len(problems) == 0 {
+		problems = []string{"running"}
+	}
+	c.Message = strings.Join(problems, "; ")
+
+	return c
+}
+
+// pdbChecks checks the disruption budgets selecting the cluster pods. The budget blocking all the
+// disruptions makes the node drains hang, so it is reported as the warning
+func (p Cmd) pdbChecks(appName string) ([]Check, error) {
+	data, err := p.GetObjects("pdb")
+	if err == ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "get pod disruption budgets")
+	}
+	var list pdbList
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal pod disruption budgets")
+	}
+
+	var checks []Check
+	for _, pdb := range list.Items {
+		if pdb.Spec.Selector == nil || pdb.Spec.Selector.MatchLabels[instanceLabel] != appName {
+			continue
+		}
+		st := pdb.Status
+		c := Check{
+			Component: pdb.Spec.Selector.MatchLabels[componentLabel],
+			Object:    "pdb/" + pdb.Name,
+			Status:    CheckOK,
+			Message:   fmt.Sprintf("%d/%d healthy, %d disruptions allowed", st.CurrentHealthy, st.ExpectedPods, st.DisruptionsAllowed),
+		}
+		if st.CurrentHealthy < st.DesiredHealthy || st.DisruptionsAllowed == 0 {
+			c.Status = CheckWarning
+		}
+		checks = append(checks, c)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Object < checks[j].Object })
+
+	return checks, nil
+}
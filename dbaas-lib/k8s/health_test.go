@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCRCheck(t *testing.T) {
+	for _, c := range []struct {
+		cr, status, message string
+	}{
+		{`{"status":{"state":"ready"}}`, CheckOK, "ready"},
+		{`{"status":{"state":"initializing","message":["pxc: not ready"]}}`, CheckWarning, "initializing; pxc: not ready"},
+		{`{"status":{"state":"error","message":"no space left"}}`, CheckFailed, "error; no space left"},
+		{`{}`, CheckWarning, "unknown"},
+	} {
+		got := crCheck("pxc", "cluster1", []byte(c.cr))
+		if got.Status != c.status || got.Message != c.message {
+			t.Errorf("crCheck(%s) = %s %q, want %s %q", c.cr, got.Status, got.Message, c.status, c.message)
+		}
+	}
+}
+
+func TestPodCheck(t *testing.T) {
+	pod := func(phase corev1.PodPhase, ready corev1.ConditionStatus, cs ...corev1.ContainerStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1-pxc-0"},
+			Status: corev1.PodStatus{
+				Phase:             phase,
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+				ContainerStatuses: cs,
+			},
+		}
+	}
+	oom := corev1.ContainerStatus{
+		Name:                 "pxc",
+		RestartCount:         1,
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+	}
+	crash := corev1.ContainerStatus{
+		Name:         "pxc",
+		RestartCount: 5,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}
+
+	for _, c := range []struct {
+		pod     corev1.Pod
+		status  string
+		message string
+	}{
+		{pod(corev1.PodRunning, corev1.ConditionTrue), CheckOK, "running"},
+		{pod(corev1.PodRunning, corev1.ConditionTrue, oom), CheckWarning, "pxc was OOMKilled; pxc restarted 1 times"},
+		{pod(corev1.PodRunning, corev1.ConditionFalse, crash), CheckFailed, "not ready; pxc is in CrashLoopBackOff; pxc restarted 5 times"},
+		{pod(corev1.PodFailed, corev1.ConditionFalse), CheckFailed, "failed"},
+	} {
+		got := podCheck(c.pod)
+		if got.Status != c.status || got.Message != c.message {
+			t.Errorf("podCheck = %s %q, want %s %q", got.Status, got.Message, c.status, c.message)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LogsOptions are the options of the logs stream
+type LogsOptions struct {
+	// Follow keeps streaming the new lines until the containers are stopped
+	Follow bool
+	// Since limits the logs to the newer lines, zero means all the lines
+	Since time.Duration
+}
+
+// logWriter writes the lines of several streams to w, so they don't interleave
+type logWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *logWriter) writeLine(prefix string, line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := l.w.Write(append(append([]byte(prefix), line...), '\n'))
+	return err
+}
+
+// StreamLogs writes the logs of the containers of the pods matching the labels to w, each line prefixed
+// with the pod and the container names. If operatorName is given the lines of the operator logs mentioning
+// appName are written as well. Empty labels leave the cluster pods out
+func (p Cmd) StreamLogs(w io.Writer, labels, operatorName, appName string, opts LogsOptions) error {
+	lw := &logWriter{w: w}
+	type stream struct {
+		prefix string
+		filter string
+		args   []string
+	}
+	var streams []stream
+
+	if len(labels) > 0 {
+		data, err := p.GetObjectByLables("pods", labels)
+		if err != nil {
+			return errors.Wrap(err, "get pods")
+		}
+		var pods corev1.PodList
+		err = json.Unmarshal(data, &pods)
+		if err != nil {
+			return errors.Wrap(err, "unmarshal pods")
+		}
+		if len(pods.Items) == 0 && len(operatorName) == 0 {
+			return errors.Errorf("no pods found by labels %s", labels)
+		}
+		for _, po := range pods.Items {
+			for _, c := range po.Spec.Containers {
+				streams = append(streams, stream{
+					prefix: "[" + po.Name + " " + c.Name + "] ",
+					args:   []string{"logs", po.Name, "-c", c.Name},
+				})
+			}
+		}
+	}
+	if len(operatorName) > 0 {
+		streams = append(streams, stream{
+			prefix: "[operator] ",
+			filter: appName,
+			args:   []string{"logs", "-l", "name=" + operatorName, "--tail=-1"},
+		})
+	}
+
+	errs := make(chan error, len(streams))
+	for _, s := range streams {
+		args := s.args
+		if opts.Follow {
+			args = append(args, "-f")
+		}
+		if opts.Since > 0 {
+			args = append(args, "--since="+opts.Since.String())
+		}
+		if len(p.Namespace) > 0 {
+			args = append(args, "-n", p.Namespace)
+		}
+		go func(prefix, filter string, args []string) {
+			errs <- p.streamCmd(lw, prefix, filter, args...)
+		}(s.prefix, s.filter, args)
+	}
+
+	var err error
+	for range streams {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// streamCmd runs the command and writes the lines of its output containing the filter to lw
+func (p Cmd) streamCmd(lw *logWriter, prefix, filter string, args ...string) error {
+	cli := exec.Command(p.execCommand, args...)
+	cli.Env = os.Environ()
+	if len(p.environment) > 0 {
+		cli.Env = append(cli.Env, "KUBECONFIG="+p.environment)
+	}
+	var stderr bytes.Buffer
+	cli.Stderr = &stderr
+	out, err := cli.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "get stdout")
+	}
+	started := time.Now()
+	err = cli.Start()
+	if err != nil {
+		return errors.Wrapf(err, "run %s", p.execCommand)
+	}
+
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(filter) > 0 && !bytes.Contains(line, []byte(filter)) {
+			continue
+		}
+		err = lw.writeLine(prefix, line)
+		if err != nil {
+			cli.Process.Kill()
+			cli.Wait()
+			return errors.Wrap(err, "write logs")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		cli.Process.Kill()
+		cli.Wait()
+		return errors.Wrap(err, "read logs")
+	}
+
+	err = cli.Wait()
+	trace(p.execCommand, args, stderr.Bytes(), started, err)
+	if err != nil {
+		return ErrCmdRun{cmd: p.execCommand, args: args, output: bytes.TrimSpace(stderr.Bytes())}
+	}
+
+	return nil
+}
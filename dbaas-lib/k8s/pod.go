@@ -0,0 +1,177 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const podCheckInterval = 5 * time.Second
+
+// RecreatePodWithData deletes the pod together with its persistent volume claim, so the statefulset
+// brings it back with the empty data directory, and waits until the new pod is ready
+func (p Cmd) RecreatePodWithData(pod, pvc string, timeout time.Duration) error {
+	old, err := p.getPod(pod)
+	if err != nil {
+		return errors.Wrap(err, "get pod")
+	}
+
+	// the claim is protected while the pod is using it, so it is removed right after the pod is gone
+	args := []string{"delete", "pvc/" + pvc, "--wait=false"}
+	if len(p.Namespace) > 0 {
+		args = append(args, "-n", p.Namespace)
+	}
+	_, err = p.runCmd(p.execCommand, args...)
+	if err != nil {
+		return errors.Wrap(err, "delete pvc")
+	}
+	err = p.DeleteObject("pod", pod)
+	if err != nil {
+		return errors.Wrap(err, "delete pod")
+	}
+
+	return p.waitPodReady(pod, old.UID, timeout)
+}
+
+// waitPodReady waits until the pod other than the one with oldUID is ready
+func (p Cmd) waitPodReady(pod string, oldUID types.UID, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(podCheckInterval)
+
+		po, err := p.getPod(pod)
+		if err != nil || po.UID == oldUID {
+			continue
+		}
+		for _, c := range po.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				return nil
+			}
+		}
+	}
+
+	return errors.Errorf("pod %s isn't ready after %s", pod, timeout)
+}
+
+func (p Cmd) getPod(name string) (*corev1.Pod, error) {
+	data, err := p.GetObject("pod", name)
+	if err != nil {
+		return nil, err
+	}
+	pod := &corev1.Pod{}
+	err = json.Unmarshal(data, pod)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal pod")
+	}
+
+	return pod, nil
+}
+
+// Exec runs the command in the pod container
+func (p Cmd) Exec(pod, container string, command ...string) ([]byte, error) {
+	args := []string{"exec", pod, "-c", container}
+	if len(p.Namespace) > 0 {
+		args = append(args, "-n", p.Namespace)
+	}
+	args = append(args, "--")
+	args = append(args, command...)
+
+	return p.runCmd(p.execCommand, args...)
+}
+
+// ContainersReady returns the number of the pods matching the labels and the number of them
+// which have the container ready
+func (p Cmd) ContainersReady(labels, container string) (ready, total int, err error) {
+	data, err := p.GetObjectByLables("pods", labels)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "get pods")
+	}
+	var pods corev1.PodList
+	err = json.Unmarshal(data, &pods)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "unmarshal pods")
+	}
+
+	for _, po := range pods.Items {
+		total++
+		for _, c := range po.Status.ContainerStatuses {
+			if c.Name == container && c.Ready {
+				ready++
+			}
+		}
+	}
+
+	return ready, total, nil
+}
+
+// WaitContainers waits until all the pods matching the labels have the container ready or,
+// if present is false, until none of them has it
+func (p Cmd) WaitContainers(labels, container string, present bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(podCheckInterval)
+
+		data, err := p.GetObjectByLables("pods", labels)
+		if err != nil {
+			continue
+		}
+		var pods corev1.PodList
+		err = json.Unmarshal(data, &pods)
+		if err != nil {
+			return errors.Wrap(err, "unmarshal pods")
+		}
+		if len(pods.Items) > 0 && podsHaveContainer(pods.Items, container, present) {
+			return nil
+		}
+	}
+
+	if present {
+		return errors.Errorf("%s containers aren't ready after %s", container, timeout)
+	}
+	return errors.Errorf("%s containers aren't removed after %s", container, timeout)
+}
+
+func podsHaveContainer(pods []corev1.Pod, container string, present bool) bool {
+	for _, po := range pods {
+		found := false
+		for _, c := range po.Spec.Containers {
+			if c.Name == container {
+				found = true
+			}
+		}
+		if found != present {
+			return false
+		}
+		if !present {
+			continue
+		}
+		ready := false
+		for _, c := range po.Status.ContainerStatuses {
+			if c.Name == container && c.Ready {
+				ready = true
+			}
+		}
+		if !ready {
+			return false
+		}
+	}
+
+	return true
+}
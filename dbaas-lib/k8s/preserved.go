@@ -0,0 +1,389 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
+)
+
+// Annotations the volume claims are marked with when the cluster is deleted with the data preserved
+const (
+	EngineAnnotation      = "dbaas.percona.com/engine"
+	VersionAnnotation     = "dbaas.percona.com/version"
+	PreservedAtAnnotation = "dbaas.percona.com/preserved-at"
+)
+
+const (
+	instanceLabel  = "app.kubernetes.io/instance"
+	managedByLabel = "app.kubernetes.io/managed-by"
+)
+
+// PreservedData is the set of volume claims left by the deleted cluster
+type PreservedData struct {
+	Cluster string
+	// Engine is the operator name if the data was preserved before the engine was recorded
+	Engine   string
+	Operator string
+	// Version is the operator version the cluster was created with, it is empty
+	// if the data was preserved before the version was recorded
+	Version string
+	Volumes []string
+	Size    resource.Quantity
+	// Created is the creation time of the oldest volume claim
+	Created     time.Time
+	PreservedAt time.Time
+}
+
+// PreserveVolumes marks the volume claims of the cluster being deleted with the engine and the
+// version of the cluster custom resource, so the data can be checked before it is used again.
+// It returns the names of the claims
+func (p Cmd) PreserveVolumes(operatorName, appName, engine string, cr []byte) ([]string, error) {
+	version, err := CRVersion(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	pvcs, err := p.getPVCs(managedByLabel + "=" + operatorName + "," + instanceLabel + "=" + appName)
+	if err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{
+		EngineAnnotation:      engine,
+		VersionAnnotation:     version,
+		PreservedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+	}
+	var names []string
+	for _, pvc := range pvcs {
+		for k, v := range annotations {
+			err = p.Annotate("pvc", pvc.Name, k, v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "annotate pvc %s", pvc.Name)
+			}
+		}
+		names = append(names, "pvc/"+pvc.Name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// CRVersion returns the operator version the custom resource was created for
+func CRVersion(cr []byte) (string, error) {
+	var obj struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	err := json.Unmarshal(cr, &obj)
+	if err != nil {
+		return "", errors.Wrap(err, "unmarshal cluster object")
+	}
+
+	return crVersion(obj.APIVersion), nil
+}
+
+// crVersion returns the operator version from the api version of the custom resource,
+// e.g. "1.4.0" for "pxc.percona.com/v1-4-0"
+func crVersion(apiVersion string) string {
+	v := apiVersion[strings.LastIndex(apiVersion, "/")+1:]
+	// the api version of the first operators had no release in it
+	if v == "v1" {
+		return "1.1.0"
+	}
+
+	return strings.Replace(strings.TrimPrefix(v, "v"), "-", ".", -1)
+}
+
+// ListPreservedData returns the data of deleted clusters of typ managed by the operator grouped by the cluster name
+func (p Cmd) ListPreservedData(typ, operatorName string) ([]PreservedData, error) {
+	pvcs, err := p.getPVCs(managedByLabel + "=" + operatorName)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []PreservedData
+	for _, d := range groupPreservedData(pvcs) {
+		ext, err := p.IsObjExists(typ, d.Cluster)
+		if err != nil {
+			return nil, errors.Wrapf(err, "check if cluster %s exists", d.Cluster)
+		}
+		if !ext {
+			list = append(list, d)
+		}
+	}
+
+	return list, nil
+}
+
+// GetPreservedData returns the data of the deleted cluster. It returns ErrNotFound if
+// there is no data or the cluster of typ with this name exists
+func (p Cmd) GetPreservedData(typ, appName string) (PreservedData, error) {
+	ext, err := p.IsObjExists(typ, appName)
+	if err != nil {
+		return PreservedData{}, errors.Wrap(err, "check if cluster exists")
+	}
+	if ext {
+		return PreservedData{}, ErrNotFound
+	}
+	pvcs, err := p.getPVCs(instanceLabel + "=" + appName)
+	if err != nil {
+		return PreservedData{}, err
+	}
+	list := groupPreservedData(pvcs)
+	if len(list) == 0 {
+		return PreservedData{}, ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+// Check returns an error if the data can't be used by the cluster of the engine created with
+// the operator version
+func (d PreservedData) Check(operatorName, version string) error {
+	if d.Operator != operatorName {
+		return errors.Errorf("data of %s was preserved from %s cluster", d.Cluster, d.Engine)
+	}
+	if len(d.Version) > 0 && d.Version != version {
+		return errors.Errorf("data of %s was preserved from the cluster of version %s, it can't be used by version %s", d.Cluster, d.Version, version)
+	}
+
+	return nil
+}
+
+func groupPreservedData(pvcs []corev1.PersistentVolumeClaim) []PreservedData {
+	clusters := make(map[string]*PreservedData)
+	var names []string
+	for _, pvc := range pvcs {
+		name := pvc.Labels[instanceLabel]
+		if len(name) == 0 {
+			continue
+		}
+		d, ok := clusters[name]
+		if !ok {
+			d = &PreservedData{
+				Cluster:  name,
+				Engine:   pvc.Annotations[EngineAnnotation],
+				Operator: pvc.Labels[managedByLabel],
+				Version:  pvc.Annotations[VersionAnnotation],
+				Created:  pvc.CreationTimestamp.Time,
+			}
+			if len(d.Engine) == 0 {
+				d.Engine = d.Operator
+			}
+			d.PreservedAt, _ = time.Parse(time.RFC3339, pvc.Annotations[PreservedAtAnnotation])
+			clusters[name] = d
+			names = append(names, name)
+		}
+		d.Volumes = append(d.Volumes, "pvc/"+pvc.Name)
+		size, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+		if !ok {
+			size = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+		d.Size.Add(size)
+		if pvc.CreationTimestamp.Time.Before(d.Created) {
+			d.Created = pvc.CreationTimestamp.Time
+		}
+	}
+	sort.Strings(names)
+
+	list := make([]PreservedData, 0, len(names))
+	for _, name := range names {
+		sort.Strings(clusters[name].Volumes)
+		list = append(list, *clusters[name])
+	}
+
+	return list
+}
+
+// PurgePreservedData deletes the volume claims left by the deleted cluster of typ
+func (p Cmd) PurgePreservedData(typ, operatorName, appName string) error {
+	d, err := p.GetPreservedData(typ, appName)
+	if err == ErrNotFound {
+		return errors.Wrapf(dbaas.ErrNotFound, "preserved data of %s", appName)
+	} else if err != nil {
+		return err
+	}
+	if d.Operator != operatorName {
+		return errors.Errorf("data of %s was preserved from %s cluster", appName, d.Engine)
+	}
+
+	return p.deletePVC(operatorName, appName)
+}
+
+// RebindPreservedData moves the volumes of the deleted cluster to the claims named after the new
+// cluster, so its statefulsets pick them up. The volumes are retained while they are unbound
+func (p Cmd) RebindPreservedData(d PreservedData, newName string) error {
+	for _, vol := range d.Volumes {
+		name := strings.TrimPrefix(vol, "pvc/")
+		newPVC, err := renamePVC(name, d.Cluster, newName)
+		if err == nil {
+			err = p.rebindPVC(name, newPVC, newName)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "rebind %s", vol)
+		}
+	}
+
+	return nil
+}
+
+// volumeClaimPrefixes are the names of the volume claim templates of the operators statefulsets
+var volumeClaimPrefixes = []string{"datadir-", "proxydata-", "mongod-data-"}
+
+// renamePVC replaces the cluster name in the claim name made by the statefulset,
+// e.g. "datadir-old-pxc-0" becomes "datadir-new-pxc-0"
+func renamePVC(pvcName, oldName, newName string) (string, error) {
+	for _, prefix := range volumeClaimPrefixes {
+		if strings.HasPrefix(pvcName, prefix+oldName+"-") {
+			return prefix + newName + strings.TrimPrefix(pvcName, prefix+oldName), nil
+		}
+	}
+
+	return "", errors.Errorf("pvc %s isn't a volume of cluster %s", pvcName, oldName)
+}
+
+func (p Cmd) rebindPVC(name, newName, appName string) error {
+	ext, err := p.IsObjExists("persistentvolumeclaim", newName)
+	if err != nil {
+		return errors.Wrap(err, "check if pvc exists")
+	}
+	if ext {
+		return errors.Wrapf(dbaas.ErrAlreadyExists, "pvc %s", newName)
+	}
+	data, err := p.GetObject("pvc", name)
+	if err != nil {
+		return errors.Wrap(err, "get pvc")
+	}
+	pvc := corev1.PersistentVolumeClaim{}
+	err = json.Unmarshal(data, &pvc)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal pvc")
+	}
+	pv := pvc.Spec.VolumeName
+	if len(pv) == 0 {
+		return errors.New("pvc isn't bound to a volume")
+	}
+	data, err = p.GetObject("pv", pv)
+	if err != nil {
+		return errors.Wrap(err, "get pv")
+	}
+	vol := corev1.PersistentVolume{}
+	err = json.Unmarshal(data, &vol)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal pv")
+	}
+
+	// the volume would be gone with the old claim otherwise
+	err = p.Patch("pv", pv, `{"spec":{"persistentVolumeReclaimPolicy":"Retain"}}`)
+	if err != nil {
+		return errors.Wrap(err, "retain pv")
+	}
+	err = p.DeleteObject("pvc", name)
+	if err != nil {
+		return errors.Wrap(err, "delete old pvc")
+	}
+	// reserve the volume for the new claim, the uid of the old one has to be dropped
+	err = p.Patch("pv", pv, fmt.Sprintf(`{"spec":{"claimRef":{"name":%q,"namespace":%q,"uid":null,"resourceVersion":null}}}`, newName, pvc.Namespace))
+	if err != nil {
+		return errors.Wrap(err, "reserve pv")
+	}
+
+	newPVC := corev1.PersistentVolumeClaim{
+		TypeMeta: pvc.TypeMeta,
+		Spec:     pvc.Spec,
+	}
+	newPVC.Name = newName
+	newPVC.Namespace = pvc.Namespace
+	newPVC.Labels = make(map[string]string, len(pvc.Labels))
+	for k, v := range pvc.Labels {
+		newPVC.Labels[k] = v
+	}
+	newPVC.Labels[instanceLabel] = appName
+	obj, err := json.Marshal(newPVC)
+	if err != nil {
+		return errors.Wrap(err, "marshal pvc")
+	}
+	err = p.apply(string(obj))
+	if err != nil {
+		return errors.Wrap(err, "create pvc")
+	}
+
+	policy := vol.Spec.PersistentVolumeReclaimPolicy
+	if len(policy) > 0 && policy != corev1.PersistentVolumeReclaimRetain {
+		err = p.Patch("pv", pv, fmt.Sprintf(`{"spec":{"persistentVolumeReclaimPolicy":%q}}`, policy))
+		if err != nil {
+			return errors.Wrap(err, "restore pv reclaim policy")
+		}
+	}
+
+	return nil
+}
+
+func (p Cmd) getPVCs(labels string) ([]corev1.PersistentVolumeClaim, error) {
+	data, err := p.GetObjectByLables("pvc", labels)
+	if err != nil {
+		return nil, errors.Wrap(err, "get pvc")
+	}
+	var pvcs corev1.PersistentVolumeClaimList
+	err = json.Unmarshal(data, &pvcs)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal pvc list")
+	}
+
+	return pvcs.Items, nil
+}
+
+// MoveSecret renames the secret if it exists
+func (p Cmd) MoveSecret(from, to string) error {
+	ext, err := p.IsObjExists("secret", from)
+	if err != nil {
+		return errors.Wrapf(err, "check if secret %s exists", from)
+	}
+	if !ext {
+		return nil
+	}
+	err = p.CopySecret(from, to)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithMessage(p.DeleteObject("secret", from), "delete secret "+from)
+}
+
+// CopySecret creates the secret with the data of the existing one
+func (p Cmd) CopySecret(from, to string) error {
+	ext, err := p.IsObjExists("secret", to)
+	if err != nil {
+		return errors.Wrapf(err, "check if secret %s exists", to)
+	}
+	if ext {
+		return errors.Wrapf(dbaas.ErrAlreadyExists, "secret %s", to)
+	}
+
+	data, err := p.GetSecrets(from)
+	if err != nil {
+		return errors.Wrapf(err, "get secret %s", from)
+	}
+
+	return errors.Wrapf(p.CreateSecret(to, data), "create secret %s", to)
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCRVersion(t *testing.T) {
+	for apiVersion, want := range map[string]string{
+		"pxc.percona.com/v1":       "1.1.0",
+		"pxc.percona.com/v1-4-0":   "1.4.0",
+		"psmdb.percona.com/v1-3-0": "1.3.0",
+	} {
+		if got := crVersion(apiVersion); got != want {
+			t.Errorf("crVersion(%q) = %q, want %q", apiVersion, got, want)
+		}
+	}
+}
+
+func TestRenamePVC(t *testing.T) {
+	for _, c := range []struct{ pvc, old, new, want string }{
+		{"datadir-old-pxc-0", "old", "new", "datadir-new-pxc-0"},
+		{"mongod-data-db-rs0-2", "db", "db2", "mongod-data-db2-rs0-2"},
+		{"datadir-pxc-pxc-1", "pxc", "my", "datadir-my-pxc-1"},
+		{"mongod-data-data-rs0-0", "data", "new", "mongod-data-new-rs0-0"},
+		{"proxydata-old-proxysql-0", "old", "new", "proxydata-new-proxysql-0"},
+	} {
+		if got, err := renamePVC(c.pvc, c.old, c.new); err != nil || got != c.want {
+			t.Errorf("renamePVC(%q, %q, %q) = %q, %v, want %q", c.pvc, c.old, c.new, got, err, c.want)
+		}
+	}
+	if got, err := renamePVC("data-old-0", "old", "new"); err == nil {
+		t.Errorf("unknown claim is renamed to %q", got)
+	}
+}
+
+func TestGroupPreservedData(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	pvc := func(name, cluster, size string, created time.Time, annotations map[string]string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Labels:            map[string]string{instanceLabel: cluster, managedByLabel: "percona-xtradb-cluster-operator"},
+				Annotations:       annotations,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		}
+	}
+	marked := map[string]string{
+		EngineAnnotation:      "pxc",
+		VersionAnnotation:     "1.4.0",
+		PreservedAtAnnotation: now.UTC().Format(time.RFC3339),
+	}
+
+	list := groupPreservedData([]corev1.PersistentVolumeClaim{
+		pvc("datadir-b-pxc-1", "b", "6G", now, marked),
+		pvc("datadir-a-pxc-0", "a", "1G", now, nil),
+		pvc("datadir-b-pxc-0", "b", "6G", now.Add(-time.Hour), marked),
+	})
+	if len(list) != 2 {
+		t.Fatalf("got %d clusters, want 2: %+v", len(list), list)
+	}
+
+	a, b := list[0], list[1]
+	if a.Cluster != "a" || a.Engine != "percona-xtradb-cluster-operator" || len(a.Version) != 0 || !a.PreservedAt.IsZero() {
+		t.Errorf("unexpected data of unmarked volumes: %+v", a)
+	}
+	if b.Cluster != "b" || b.Engine != "pxc" || b.Version != "1.4.0" || !b.PreservedAt.Equal(now) {
+		t.Errorf("unexpected data of marked volumes: %+v", b)
+	}
+	if len(b.Volumes) != 2 || b.Volumes[0] != "pvc/datadir-b-pxc-0" {
+		t.Errorf("unexpected volumes: %v", b.Volumes)
+	}
+	if b.Size.String() != "12G" {
+		t.Errorf("size is %s, want 12G", b.Size.String())
+	}
+	if !b.Created.Equal(now.Add(-time.Hour)) {
+		t.Errorf("created at %s, want the oldest volume time %s", b.Created, now.Add(-time.Hour))
+	}
+
+	if err := b.Check("percona-xtradb-cluster-operator", "1.3.0"); err == nil {
+		t.Error("version mismatch isn't detected")
+	}
+	if err := b.Check("percona-server-mongodb-operator", "1.4.0"); err == nil {
+		t.Error("engine mismatch isn't detected")
+	}
+	if err := a.Check("percona-xtradb-cluster-operator", "1.3.0"); err != nil {
+		t.Errorf("data without version should be accepted: %v", err)
+	}
+}
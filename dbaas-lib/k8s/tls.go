@@ -0,0 +1,247 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Keys of the TLS secret data the operators expect
+const (
+	TLSCAKey   = "ca.crt"
+	TLSCertKey = corev1.TLSCertKey
+	TLSKeyKey  = corev1.TLSPrivateKeyKey
+)
+
+const (
+	tlsKeyBits  = 2048
+	tlsValidFor = 10 * 365 * 24 * time.Hour
+)
+
+// CA is the self-signed certificate authority used to issue the cluster certificates
+// when cert-manager isn't available
+type CA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *rsa.PrivateKey
+}
+
+// NewCA generates the new self-signed CA
+func NewCA(commonName string) (*CA, error) {
+	key, err := rsa.GenerateKey(rand.Reader, tlsKeyBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate key")
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Percona"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(tlsValidFor),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "create certificate")
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse certificate")
+	}
+
+	return &CA{
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:     key,
+	}, nil
+}
+
+// Issue returns the data of TLS secret with the certificate for the given hosts signed by the CA.
+// The certificate is valid both for server and client authentication as it is used for
+// intra-cluster traffic as well
+func (ca *CA) Issue(hosts []string) (map[string][]byte, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("no hosts for certificate")
+	}
+	key, err := rsa.GenerateKey(rand.Reader, tlsKeyBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate key")
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: hosts[0], Organization: []string{"Percona"}},
+		DNSNames:              hosts,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(tlsValidFor),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "create certificate")
+	}
+
+	return map[string][]byte{
+		TLSCAKey:   ca.certPEM,
+		TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		TLSKeyKey:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}, nil
+}
+
+func serialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "generate serial number")
+	}
+
+	return serial, nil
+}
+
+// CAFingerprint returns SHA-256 fingerprint of the PEM encoded CA certificate
+func CAFingerprint(caPEM []byte) (string, error) {
+	block, _ := pem.Decode(caPEM)
+	if block == nil {
+		return "", errors.New("no PEM data found")
+	}
+	sum := sha256.Sum256(block.Bytes)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hex, ":"), nil
+}
+
+// IsCertManagerInstalled checks if cert-manager CRDs are present in the cluster
+func (p Cmd) IsCertManagerInstalled() (bool, error) {
+	for _, crd := range []string{"certificates.cert-manager.io", "certificates.certmanager.k8s.io"} {
+		ext, err := p.IsObjExists("crd", crd)
+		if err != nil {
+			return false, errors.Wrapf(err, "check crd %s", crd)
+		}
+		if ext {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CheckTLSSecret checks if the secret has all keys needed by the operator
+func (p Cmd) CheckTLSSecret(name string) error {
+	data, err := p.GetSecrets(name)
+	if err != nil {
+		return errors.Wrapf(err, "get secret %s", name)
+	}
+	for _, k := range []string{TLSCAKey, TLSCertKey, TLSKeyKey} {
+		if len(data[k]) == 0 {
+			return errors.Errorf("secret %s has no %s", name, k)
+		}
+	}
+
+	return nil
+}
+
+// SetupTLSSecrets makes sure that secrets with certificates exist. The secrets argument maps
+// secret name to the hosts the certificate should be valid for. Existing secrets are kept as is.
+// Missing certificates are left to the operator and cert-manager if the last one is installed,
+// otherwise they are signed by the new self-signed CA. It returns the names of the created secrets,
+// so the caller removes them if the cluster isn't created
+func (p Cmd) SetupTLSSecrets(clusterName string, secrets map[string][]string) ([]string, error) {
+	missing := make(map[string][]string)
+	for name, hosts := range secrets {
+		ext, err := p.IsObjExists("secret", name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "check if secret %s exists", name)
+		}
+		if ext {
+			err = p.CheckTLSSecret(name)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		missing[name] = hosts
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	cm, err := p.IsCertManagerInstalled()
+	if err != nil {
+		return nil, errors.Wrap(err, "check cert-manager")
+	}
+	if cm {
+		return nil, nil
+	}
+
+	ca, err := NewCA(clusterName + "-ca")
+	if err != nil {
+		return nil, errors.Wrap(err, "create CA")
+	}
+	var created []string
+	for name, hosts := range missing {
+		data, err := ca.Issue(hosts)
+		if err == nil {
+			err = p.createSecret(name, corev1.SecretTypeTLS, data)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "create secret %s", name)
+			if derr := p.DeleteSecrets(created); derr != nil {
+				err = errors.WithMessagef(err, "%v", derr)
+			}
+			return nil, err
+		}
+		created = append(created, name)
+	}
+
+	return created, nil
+}
+
+// DeleteSecrets deletes the secrets with the given names
+func (p Cmd) DeleteSecrets(names []string) error {
+	for _, name := range names {
+		err := p.DeleteObject("secret", name)
+		if err != nil {
+			return errors.Wrapf(err, "delete secret %s", name)
+		}
+	}
+
+	return nil
+}
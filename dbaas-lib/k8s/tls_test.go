@@ -0,0 +1,51 @@
+package k8s
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+func TestCAIssue(t *testing.T) {
+	ca, err := NewCA("test-ca")
+	if err != nil {
+		t.Fatal("new CA:", err)
+	}
+	data, err := ca.Issue([]string{"test-pxc", "*.test-pxc"})
+	if err != nil {
+		t.Fatal("issue:", err)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data[TLSCAKey]) {
+		t.Fatal("no CA certificate in", TLSCAKey)
+	}
+	block, _ := pem.Decode(data[TLSCertKey])
+	if block == nil {
+		t.Fatal("no certificate in", TLSCertKey)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal("parse certificate:", err)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:   "test-pxc-0.test-pxc",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		t.Error("verify certificate:", err)
+	}
+	if len(data[TLSKeyKey]) == 0 {
+		t.Error("no key in", TLSKeyKey)
+	}
+
+	fp, err := CAFingerprint(data[TLSCAKey])
+	if err != nil {
+		t.Fatal("fingerprint:", err)
+	}
+	if len(strings.Split(fp, ":")) != 32 {
+		t.Errorf("unexpected fingerprint %s", fp)
+	}
+}
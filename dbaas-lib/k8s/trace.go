@@ -0,0 +1,27 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import "time"
+
+// Tracer gets every executed command with its output, the time it took and the error.
+// It is nil by default, the CLI sets it to show the commands in the verbose mode
+var Tracer func(cmd string, args []string, output []byte, took time.Duration, err error)
+
+func trace(cmd string, args []string, output []byte, started time.Time, err error) {
+	if Tracer != nil {
+		Tracer(cmd, args, output, time.Since(started), err)
+	}
+}
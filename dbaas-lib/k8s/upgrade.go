@@ -18,6 +18,8 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 )
 
 func (p Cmd) Upgrade(typ string, clusterName, cr string) error {
@@ -30,7 +32,7 @@ func (p Cmd) Upgrade(typ string, clusterName, cr string) error {
 		return errors.Wrap(err, "check if cluster exists")
 	}
 	if !ext {
-		return errors.New("cluster '" + clusterName + "' not exist")
+		return errors.Wrapf(dbaas.ErrNotFound, "cluster %s/%s", typ, clusterName)
 	}
 	err = p.apply(cr)
 	if err != nil {
@@ -0,0 +1,204 @@
+// Copyright © 2019 Percona, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// ExpandPVCs requests the new size for the persistent volume claims matching the label selector
+// and waits until the volumes are resized if timeout isn't zero. The storage classes of all
+// claims have to allow volume expansion, volumes can't be shrunk. It returns the claims
+// which file systems are resized only when their pods are restarted
+func (p Cmd) ExpandPVCs(labels, size string, timeout time.Duration) ([]string, error) {
+	newSize, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse size %s", size)
+	}
+
+	data, err := p.GetObjectByLables("pvc", labels)
+	if err != nil {
+		return nil, errors.Wrap(err, "get pvc")
+	}
+	var pvcs corev1.PersistentVolumeClaimList
+	err = json.Unmarshal(data, &pvcs)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal pvc list")
+	}
+	if len(pvcs.Items) == 0 {
+		return nil, errors.Errorf("no pvc found by labels %s", labels)
+	}
+
+	var resize []string
+	for _, pvc := range pvcs.Items {
+		err = p.checkVolumeExpansion(pvc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pvc %s", pvc.Name)
+		}
+		cur := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		switch newSize.Cmp(cur) {
+		case -1:
+			return nil, errors.Errorf("pvc %s has %s, volumes can't be shrunk", pvc.Name, cur.String())
+		case 1:
+			resize = append(resize, pvc.Name)
+		}
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"resources":{"requests":{"storage":"%s"}}}}`, newSize.String())
+	for _, name := range resize {
+		err = p.Patch("pvc", name, patch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "patch pvc %s", name)
+		}
+	}
+	if timeout == 0 {
+		return nil, nil
+	}
+
+	return p.waitPVCsResized(resize, newSize, timeout)
+}
+
+func (p Cmd) checkVolumeExpansion(pvc corev1.PersistentVolumeClaim) error {
+	sc, err := p.storageClass(pvc)
+	if err != nil {
+		return err
+	}
+	if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
+		return errors.Errorf("storage class %s doesn't allow volume expansion", sc.Name)
+	}
+
+	return nil
+}
+
+// storageClass returns the storage class of the claim or the default one if the claim has no class
+func (p Cmd) storageClass(pvc corev1.PersistentVolumeClaim) (*storagev1.StorageClass, error) {
+	if pvc.Spec.StorageClassName != nil && len(*pvc.Spec.StorageClassName) > 0 {
+		data, err := p.GetObject("storageclass", *pvc.Spec.StorageClassName)
+		if err != nil {
+			return nil, errors.Wrap(err, "get storage class")
+		}
+		sc := &storagev1.StorageClass{}
+		err = json.Unmarshal(data, sc)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal storage class")
+		}
+		return sc, nil
+	}
+
+	data, err := p.GetObjects("storageclass")
+	if err != nil {
+		return nil, errors.Wrap(err, "get storage classes")
+	}
+	var list storagev1.StorageClassList
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal storage classes")
+	}
+	for i, c := range list.Items {
+		if c.Annotations[defaultStorageClassAnnotation] == "true" {
+			return &list.Items[i], nil
+		}
+	}
+
+	return nil, errors.New("no storage class and no default one")
+}
+
+// waitPVCsResized waits until the capacity of all claims reaches the size
+// waitPVCsResized waits until the volumes of the claims have the size. The claims with
+// FileSystemResizePending condition are resized as far as it can be done online, the names
+// of them are returned, as the file systems are resized only when the pods are restarted
+func (p Cmd) waitPVCsResized(names []string, size resource.Quantity, timeout time.Duration) ([]string, error) {
+	pending := make(map[string]string)
+	for _, n := range names {
+		pending[n] = "waiting for resize"
+	}
+	var restart []string
+	deadline := time.Now().Add(timeout)
+	for len(pending) > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Second)
+
+		for name := range pending {
+			data, err := p.GetObject("pvc", name)
+			if err != nil {
+				continue
+			}
+			pvc := corev1.PersistentVolumeClaim{}
+			err = json.Unmarshal(data, &pvc)
+			if err != nil {
+				return restart, errors.Wrap(err, "unmarshal pvc")
+			}
+			capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+			if capacity.Cmp(size) >= 0 && len(pvc.Status.Conditions) == 0 {
+				delete(pending, name)
+				continue
+			}
+			for _, c := range pvc.Status.Conditions {
+				if c.Type == corev1.PersistentVolumeClaimFileSystemResizePending {
+					delete(pending, name)
+					restart = append(restart, name)
+					break
+				}
+				pending[name] = string(c.Type)
+				if len(c.Message) > 0 {
+					pending[name] += ": " + c.Message
+				}
+			}
+		}
+	}
+	sort.Strings(restart)
+	if len(pending) == 0 {
+		return restart, nil
+	}
+
+	var st []string
+	for name, cond := range pending {
+		st = append(st, name+" ("+cond+")")
+	}
+	sort.Strings(st)
+	return restart, errors.Errorf("volumes aren't resized after %s: %s", timeout, strings.Join(st, ", "))
+}
+
+// Patch applies the merge patch to the object
+func (p Cmd) Patch(typ, name, patch string) error {
+	args := []string{"patch", typ + "/" + name, "--type=merge", "-p", patch}
+	if len(p.Namespace) > 0 {
+		args = append(args, "-n", p.Namespace)
+	}
+	_, err := p.runCmd(p.execCommand, args...)
+
+	return err
+}
+
+// DeleteOrphan deletes the object leaving the objects it owns, like pods of a statefulset, in place
+func (p Cmd) DeleteOrphan(typ, name string) error {
+	args := []string{"delete", typ + "/" + name, "--cascade=false"}
+	if len(p.Namespace) > 0 {
+		args = append(args, "-n", p.Namespace)
+	}
+	_, err := p.runCmd(p.execCommand, args...)
+
+	return err
+}
@@ -0,0 +1,67 @@
+package dbaas
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// reservedLabelPrefix is the prefix of the labels the operators select the cluster objects by
+const reservedLabelPrefix = "app.kubernetes.io/"
+
+// ParseLabels parses the comma separated "key=value" pairs, e.g. "team=payments,env=prod"
+func ParseLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if len(strings.TrimSpace(s)) == 0 {
+		return labels, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("label %q should be in the key=value format", pair)
+		}
+		err := ValidateLabel(kv[0], kv[1])
+		if err != nil {
+			return nil, err
+		}
+		labels[kv[0]] = kv[1]
+	}
+
+	return labels, nil
+}
+
+// ValidateLabel checks the key and the value are valid for Kubernetes and the key isn't used by the operators
+func ValidateLabel(key, value string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return errors.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+	}
+	if strings.HasPrefix(key, reservedLabelPrefix) {
+		return errors.Errorf("label key %q is reserved for the operator", key)
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return errors.Errorf("invalid value of label %q: %s", key, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// ReplaceLabels returns the copy of labels with the keys of old removed and the new ones added.
+// It keeps the pod labels set with the engine options when the cluster labels are changed
+func ReplaceLabels(labels, old, new map[string]string) map[string]string {
+	res := make(map[string]string, len(labels)+len(new))
+	for k, v := range labels {
+		if _, ok := old[k]; !ok {
+			res[k] = v
+		}
+	}
+	for k, v := range new {
+		res[k] = v
+	}
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
@@ -0,0 +1,38 @@
+package dbaas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels("team=payments, env=prod,empty=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"team": "payments", "env": "prod", "empty": ""}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("got %v, want %v", labels, want)
+	}
+
+	for _, s := range []string{
+		"team",
+		"team=pay ments",
+		"-team=payments",
+		"app.kubernetes.io/instance=other",
+	} {
+		if _, err := ParseLabels(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestReplaceLabels(t *testing.T) {
+	pod := map[string]string{"sidecar": "on", "team": "payments"}
+	got := ReplaceLabels(pod, map[string]string{"team": "payments"}, map[string]string{"env": "prod"})
+	if want := map[string]string{"sidecar": "on", "env": "prod"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := ReplaceLabels(map[string]string{"team": "payments"}, map[string]string{"team": "payments"}, nil); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
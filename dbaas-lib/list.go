@@ -0,0 +1,111 @@
+package dbaas
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListFilter selects the databases returned by ListAllDB
+type ListFilter struct {
+	// Labels is the label selector, e.g. "team=payments,env!=dev". Empty selects all the databases
+	Labels string
+	// Status selects the databases in the given state. Empty selects all the databases
+	Status State
+}
+
+// ListAllDB lists the databases of all the registered providers and engines concurrently.
+// An engine failing to list its databases doesn't fail the whole list, its error is returned
+// along with the databases found by the other engines
+func ListAllDB(filter ListFilter) ([]DB, []error, error) {
+	// the selector is checked before querying the engines
+	_, err := FilterDB(nil, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type result struct {
+		dbs []DB
+		err error
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []result
+	)
+	for pName, p := range Providers {
+		for eName, e := range p.Engines {
+			wg.Add(1)
+			go func(pName, eName string, e Engine) {
+				defer wg.Done()
+				dbs, err := e.GetDBClusterList()
+				if err != nil {
+					err = errors.Wrapf(err, "list %s/%s databases", pName, eName)
+				}
+				for i := range dbs {
+					if len(dbs[i].Provider) == 0 {
+						dbs[i].Provider = pName
+					}
+					if len(dbs[i].Engine) == 0 {
+						dbs[i].Engine = eName
+					}
+				}
+				mu.Lock()
+				results = append(results, result{dbs: dbs, err: err})
+				mu.Unlock()
+			}(pName, eName, e)
+		}
+	}
+	wg.Wait()
+
+	var (
+		list []DB
+		errs []error
+	)
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+		list = append(list, r.dbs...)
+	}
+	list, err = FilterDB(list, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Provider != list[j].Provider {
+			return list[i].Provider < list[j].Provider
+		}
+		if list[i].Engine != list[j].Engine {
+			return list[i].Engine < list[j].Engine
+		}
+		return list[i].ResourceName < list[j].ResourceName
+	})
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+
+	return list, errs, nil
+}
+
+// FilterDB returns the databases matching the label selector and the status of the filter
+func FilterDB(list []DB, filter ListFilter) ([]DB, error) {
+	selector, err := labels.Parse(filter.Labels)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse label selector %q", filter.Labels)
+	}
+
+	var res []DB
+	for _, db := range list {
+		if len(filter.Status) > 0 && !strings.EqualFold(string(db.Status), string(filter.Status)) {
+			continue
+		}
+		if !selector.Matches(labels.Set(db.Labels)) {
+			continue
+		}
+		res = append(res, db)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package dbaas
+
+import (
+	"errors"
+	"testing"
+)
+
+type listEngine struct {
+	Engine
+	dbs []DB
+	err error
+}
+
+func (e listEngine) GetDBClusterList() ([]DB, error) {
+	return e.dbs, e.err
+}
+
+func TestListAllDB(t *testing.T) {
+	providers := Providers
+	defer func() { Providers = providers }()
+	Providers = map[string]Provider{
+		"k8s": {Engines: map[string]Engine{
+			"pxc": listEngine{dbs: []DB{
+				{ResourceName: "orders", Status: StateReady, Labels: map[string]string{"team": "payments"}},
+				{ResourceName: "billing", Status: StateInit, Labels: map[string]string{"team": "payments"}},
+			}},
+			"psmdb": listEngine{dbs: []DB{
+				{ResourceName: "events", Status: StateReady},
+			}},
+			"broken": listEngine{err: errors.New("connection refused")},
+		}},
+	}
+
+	list, errs, err := ListAllDB(ListFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected 1 engine error, got %v", errs)
+	}
+	var names []string
+	for _, db := range list {
+		names = append(names, db.Engine+"/"+db.ResourceName)
+		if db.Provider != "k8s" {
+			t.Errorf("%s: provider %q", db.ResourceName, db.Provider)
+		}
+	}
+	if got, want := len(names), 3; got != want || names[0] != "psmdb/events" || names[1] != "pxc/billing" || names[2] != "pxc/orders" {
+		t.Errorf("unexpected list %v", names)
+	}
+
+	list, _, err = ListAllDB(ListFilter{Labels: "team=payments", Status: "Ready"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ResourceName != "orders" {
+		t.Errorf("unexpected filtered list %v", list)
+	}
+
+	_, _, err = ListAllDB(ListFilter{Labels: "team in payments"})
+	if err == nil {
+		t.Error("expected selector error")
+	}
+}
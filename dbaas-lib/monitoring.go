@@ -0,0 +1,15 @@
+package dbaas
+
+import "time"
+
+// Monitoring is the PMM client setup of the cluster
+type Monitoring struct {
+	// ServerHost is the PMM server address, empty keeps the current one
+	ServerHost string
+	// ServerUser is the PMM server user, empty keeps the current one
+	ServerUser string
+	// PasswordSecret is the name of the secret with the PMM server password under the "password" key
+	PasswordSecret string
+	// Wait is how long to wait for the PMM client sidecars, zero means don't wait
+	Wait time.Duration
+}
@@ -1,11 +1,14 @@
 package options
 
 import (
+	"encoding/json"
 	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 )
 
 // Parse parses options from the given string in format "object.paramValue=val,objectTwo.paramValue=val"
@@ -22,7 +25,7 @@ func Parse(to interface{}, typ reflect.Type, options string) error {
 		v := strings.Split(str, "=")
 		v[0] = strings.ToLower(v[0])
 		if _, ok := opts[getStringWithoutSquareBrackets(v[0])]; !ok {
-			return errors.Errorf("invalid option %s", v[0])
+			return dbaas.ErrInvalidOption{Path: v[0]}
 		}
 		if len(v) > 1 {
 			fs := strings.Split(opts[getStringWithoutSquareBrackets(v[0])], ".")
@@ -47,7 +50,7 @@ func Parse(to interface{}, typ reflect.Type, options string) error {
 			}
 			err := setValue(rv, v[1])
 			if err != nil {
-				return errors.Wrapf(err, "set value %s=%s", v[0], v[1])
+				return dbaas.ErrInvalidOption{Path: v[0], Err: errors.Wrapf(err, "set value %s", v[1])}
 			}
 		}
 	}
@@ -81,6 +84,20 @@ func setValue(val reflect.Value, value string) error {
 		// TODO: maps, slices
 		return errors.Errorf("type %v not implemented", val.Kind())
 	case reflect.Struct:
+		// types like resource.Quantity know how to parse themselves from the JSON string
+		if !val.CanAddr() {
+			break
+		}
+		if u, ok := val.Addr().Interface().(json.Unmarshaler); ok {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return errors.Errorf("parse value %s: %v", value, err)
+			}
+			err = u.UnmarshalJSON(b)
+			if err != nil {
+				return errors.Errorf("parse value %s: %v", value, err)
+			}
+		}
 	/*v, err := parseStructValue(value, val)
 	if err != nil {
 		return errors.Errorf("parse value %s: %v", val, err)
@@ -220,6 +237,9 @@ func validConfKeys(t reflect.Type, to map[string]string, pk, pv string) {
 		}
 
 		if fieldType.Kind() == reflect.Struct {
+			if isUnmarshaler(fieldType) {
+				to[strings.ToLower(name)] = kt
+			}
 			validConfKeys(fieldType, to, name, kt)
 		} else if fieldType.Kind() == reflect.Slice {
 			validConfKeys(fieldType.Elem(), to, name, kt)
@@ -228,3 +248,10 @@ func validConfKeys(t reflect.Type, to map[string]string, pk, pv string) {
 		}
 	}
 }
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// isUnmarshaler checks if the struct value can be set as a whole rather than by fields
+func isUnmarshaler(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(unmarshalerType)
+}
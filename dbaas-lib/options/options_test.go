@@ -4,6 +4,12 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib"
 	"github.com/Percona-Lab/percona-dbaas-cli/dbaas-lib/options"
 )
 
@@ -61,3 +67,37 @@ func TestOptions(t *testing.T) {
 		t.Errorf("not equal: %v", v)
 	}
 }
+
+func TestOptionsQuantity(t *testing.T) {
+	type Volume struct {
+		Requests corev1.ResourceList `json:"requests"`
+		Size     resource.Quantity   `json:"size"`
+	}
+	type T struct {
+		Volume *Volume `json:"volume"`
+	}
+
+	v := T{}
+	err := options.Parse(&v, reflect.TypeOf(v), "volume.requests=storage:10G,volume.size=512Mi")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	storage := v.Volume.Requests[corev1.ResourceStorage]
+	if storage.Cmp(resource.MustParse("10G")) != 0 {
+		t.Errorf("wrong storage request: %s", storage.String())
+	}
+	if v.Volume.Size.Cmp(resource.MustParse("512Mi")) != 0 {
+		t.Errorf("wrong size: %s", v.Volume.Size.String())
+	}
+
+	err = options.Parse(&v, reflect.TypeOf(v), "volume.size=ten")
+	var invalid dbaas.ErrInvalidOption
+	if !errors.As(err, &invalid) || invalid.Path != "volume.size" {
+		t.Errorf("expected the invalid option error for the invalid quantity, got %v", err)
+	}
+	err = options.Parse(&v, reflect.TypeOf(v), "volume.color=red")
+	if !errors.As(err, &invalid) || invalid.Path != "volume.color" {
+		t.Errorf("expected the invalid option error for the unknown option, got %v", err)
+	}
+}
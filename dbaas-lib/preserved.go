@@ -0,0 +1,16 @@
+package dbaas
+
+import "time"
+
+// PreservedData is the data left by the cluster deleted with the data preserved
+type PreservedData struct {
+	Cluster string `json:"cluster"`
+	Engine  string `json:"engine"`
+	// Version is the operator version the cluster was created with, it is unknown for the data
+	// preserved by the older versions of the tool
+	Version     string    `json:"version,omitempty"`
+	Volumes     []string  `json:"volumes"`
+	Size        string    `json:"size"`
+	Created     time.Time `json:"created"`
+	PreservedAt time.Time `json:"preservedAt,omitempty"`
+}
@@ -0,0 +1,162 @@
+package dbaas
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// PresetAnnotation is the annotation of the cluster object the preset it was created with is recorded in
+const PresetAnnotation = AnnotationPrefix + "preset"
+
+// Preset is a named sizing profile for the clusters. Engines pick the components they have
+// and ignore the rest, so the same preset can be used for any engine
+type Preset struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Components  map[string]Component `json:"components"`
+}
+
+// Component is the sizing of a cluster component: "pxc" and "proxysql" for pxc, "mongod" for psmdb
+type Component struct {
+	Size     int32     `json:"size,omitempty"`
+	Requests Resources `json:"requests,omitempty"`
+	Limits   Resources `json:"limits,omitempty"`
+	Storage  string    `json:"storage,omitempty"`
+}
+
+// Resources are CPU and memory amounts in the kubernetes quantity format
+type Resources struct {
+	CPU    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+// Options returns the component sizing in "object.paramValue=val" format for the pod spec at the given path
+func (c Component) Options(path string) []string {
+	var opts []string
+	if c.Size > 0 {
+		opts = append(opts, fmt.Sprintf("%s.size=%d", path, c.Size))
+	}
+	for _, r := range []struct {
+		key, val string
+	}{
+		{"resources.requests.cpu", c.Requests.CPU},
+		{"resources.requests.memory", c.Requests.Memory},
+		{"resources.limits.cpu", c.Limits.CPU},
+		{"resources.limits.memory", c.Limits.Memory},
+		{"volumespec.persistentvolumeclaim.resources.requests", c.Storage},
+	} {
+		if len(r.val) == 0 {
+			continue
+		}
+		if r.key == "volumespec.persistentvolumeclaim.resources.requests" {
+			r.val = "storage:" + r.val
+		}
+		opts = append(opts, path+"."+r.key+"="+r.val)
+	}
+
+	return opts
+}
+
+var presets = struct {
+	sync.RWMutex
+	m map[string]Preset
+}{m: make(map[string]Preset)}
+
+func init() {
+	for _, p := range []Preset{
+		{
+			Name:        "small",
+			Description: "Minimal highly available cluster for development and testing",
+			Components: map[string]Component{
+				"pxc":      {Size: 3, Requests: Resources{CPU: "600m", Memory: "1G"}, Limits: Resources{CPU: "1", Memory: "2G"}, Storage: "6G"},
+				"proxysql": {Size: 1, Requests: Resources{CPU: "300m", Memory: "500M"}, Limits: Resources{CPU: "500m", Memory: "1G"}, Storage: "1G"},
+				"mongod":   {Size: 3, Requests: Resources{CPU: "300m", Memory: "500M"}, Limits: Resources{CPU: "600m", Memory: "1G"}, Storage: "3G"},
+			},
+		},
+		{
+			Name:        "medium",
+			Description: "Cluster for moderate production workloads",
+			Components: map[string]Component{
+				"pxc":      {Size: 3, Requests: Resources{CPU: "2", Memory: "4G"}, Limits: Resources{CPU: "2", Memory: "4G"}, Storage: "50G"},
+				"proxysql": {Size: 2, Requests: Resources{CPU: "500m", Memory: "1G"}, Limits: Resources{CPU: "1", Memory: "1G"}, Storage: "2G"},
+				"mongod":   {Size: 3, Requests: Resources{CPU: "2", Memory: "4G"}, Limits: Resources{CPU: "2", Memory: "4G"}, Storage: "50G"},
+			},
+		},
+		{
+			Name:        "large",
+			Description: "Cluster for heavy production workloads",
+			Components: map[string]Component{
+				"pxc":      {Size: 5, Requests: Resources{CPU: "4", Memory: "16G"}, Limits: Resources{CPU: "4", Memory: "16G"}, Storage: "200G"},
+				"proxysql": {Size: 3, Requests: Resources{CPU: "1", Memory: "2G"}, Limits: Resources{CPU: "2", Memory: "2G"}, Storage: "2G"},
+				"mongod":   {Size: 5, Requests: Resources{CPU: "4", Memory: "16G"}, Limits: Resources{CPU: "4", Memory: "16G"}, Storage: "200G"},
+			},
+		},
+	} {
+		RegisterPreset(p)
+	}
+}
+
+// RegisterPreset adds the preset or replaces the one with the same name
+func RegisterPreset(p Preset) {
+	presets.Lock()
+	presets.m[p.Name] = p
+	presets.Unlock()
+}
+
+// GetPreset returns the preset by name
+func GetPreset(name string) (Preset, error) {
+	presets.RLock()
+	p, ok := presets.m[name]
+	presets.RUnlock()
+	if !ok {
+		return p, errors.Errorf("unknown preset %s, available presets: %s", name, strings.Join(PresetNames(), ", "))
+	}
+
+	return p, nil
+}
+
+// PresetNames returns the sorted names of available presets
+func PresetNames() []string {
+	presets.RLock()
+	defer presets.RUnlock()
+	names := make([]string, 0, len(presets.m))
+	for n := range presets.m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// PresetsFile is the format of the user presets config file
+type PresetsFile struct {
+	Presets []Preset `json:"presets"`
+}
+
+// LoadPresets registers presets from the YAML or JSON config file. The presets
+// with the names of the built-in ones replace them
+func LoadPresets(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return errors.Wrap(err, "read file")
+	}
+	var f PresetsFile
+	err = yaml.Unmarshal(data, &f)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s", file)
+	}
+	for _, p := range f.Presets {
+		if len(p.Name) == 0 {
+			return errors.Errorf("preset without name in %s", file)
+		}
+		RegisterPreset(p)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package dbaas
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Spec is the typed options of the engine, e.g. pxc.Options or psmdb.Options of the engine packages.
+// It is the alternative to the "spec.pxc.size=3,..." string of Instance.EngineOptions
+type Spec interface {
+	// Engine is the name of the engine the options are for
+	Engine() string
+	// EngineOptions returns the options in the format of Instance.EngineOptions
+	EngineOptions() (string, error)
+}
+
+// ResourceRequirements are the CPU and memory requests and limits of the pod
+type ResourceRequirements struct {
+	Requests Resources
+	Limits   Resources
+}
+
+// CreateDBWithSpec creates the database with the typed engine options. The engine is the one
+// of the spec if the instance doesn't set it. Instance.EngineOptions are applied over the spec
+func CreateDBWithSpec(instance Instance, spec Spec) error {
+	instance, err := withSpec(instance, spec)
+	if err != nil {
+		return err
+	}
+
+	return CreateDB(instance)
+}
+
+// ModifyDBWithSpec changes the database with the typed engine options. Only the set fields are changed
+func ModifyDBWithSpec(instance Instance, spec Spec) error {
+	instance, err := withSpec(instance, spec)
+	if err != nil {
+		return err
+	}
+
+	return ModifyDB(instance)
+}
+
+func withSpec(instance Instance, spec Spec) (Instance, error) {
+	if spec == nil {
+		return instance, nil
+	}
+	if len(instance.Engine) == 0 {
+		instance.Engine = spec.Engine()
+	}
+	if instance.Engine != spec.Engine() {
+		return instance, errors.Errorf("%s options can't be used with %s engine", spec.Engine(), instance.Engine)
+	}
+	opts, err := spec.EngineOptions()
+	if err != nil {
+		return instance, errors.Wrapf(err, "%s options", spec.Engine())
+	}
+	if len(instance.EngineOptions) > 0 {
+		opts = strings.Trim(opts+","+instance.EngineOptions, ",")
+	}
+	instance.EngineOptions = opts
+
+	return instance, nil
+}
+
+// Validate checks the resources and the storage of the component at the options path are valid
+// quantities, so the typed options fail with ErrInvalidOption before the cluster is changed
+func (c Component) Validate(path string) error {
+	if c.Size < 0 {
+		return ErrInvalidOption{Path: path + ".size", Err: errors.Errorf("negative size %d", c.Size)}
+	}
+	for _, q := range []struct {
+		key, val string
+	}{
+		{"resources.requests.cpu", c.Requests.CPU},
+		{"resources.requests.memory", c.Requests.Memory},
+		{"resources.limits.cpu", c.Limits.CPU},
+		{"resources.limits.memory", c.Limits.Memory},
+		{"volumespec.persistentvolumeclaim.resources.requests", c.Storage},
+	} {
+		if len(q.val) == 0 {
+			continue
+		}
+		if _, err := resource.ParseQuantity(q.val); err != nil {
+			return ErrInvalidOption{Path: path + "." + q.key, Err: errors.Errorf("invalid quantity %q", q.val)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package dbaas
+
+import (
+	"errors"
+	"testing"
+)
+
+type specEngine struct {
+	Engine
+	created Instance
+	opts    string
+}
+
+func (e *specEngine) CreateDBCluster(instance Instance) error {
+	e.created = instance
+	return nil
+}
+
+func (e *specEngine) UpdateDBCluster(name, opts, version string) error {
+	e.opts = opts
+	return nil
+}
+
+type testSpec struct {
+	opts string
+	err  error
+}
+
+func (s testSpec) Engine() string { return "test" }
+
+func (s testSpec) EngineOptions() (string, error) { return s.opts, s.err }
+
+func TestCreateDBWithSpec(t *testing.T) {
+	providers := Providers
+	defer func() { Providers = providers }()
+	e := &specEngine{}
+	Providers = map[string]Provider{"k8s": {Engines: map[string]Engine{"test": e, "other": e}}}
+
+	err := CreateDBWithSpec(Instance{Provider: "k8s", Name: "orders"}, testSpec{opts: "spec.size=3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.created.Engine != "test" || e.created.EngineOptions != "spec.size=3" {
+		t.Errorf("unexpected instance %+v", e.created)
+	}
+
+	// the options string overrides the spec
+	err = ModifyDBWithSpec(Instance{Provider: "k8s", Engine: "test", Name: "orders", EngineOptions: "spec.size=5"}, testSpec{opts: "spec.size=3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.opts != "spec.size=3,spec.size=5" {
+		t.Errorf("unexpected options %q", e.opts)
+	}
+
+	err = CreateDBWithSpec(Instance{Provider: "k8s", Engine: "other", Name: "orders"}, testSpec{})
+	if err == nil {
+		t.Error("expected the error for the options of another engine")
+	}
+	err = CreateDBWithSpec(Instance{Provider: "k8s", Name: "orders"}, testSpec{err: errors.New("invalid storage")})
+	if err == nil {
+		t.Error("expected the error of the invalid options")
+	}
+}
+
+func TestComponentValidate(t *testing.T) {
+	valid := Component{Size: 3, Requests: Resources{CPU: "600m", Memory: "1G"}, Storage: "6Gi"}
+	if err := valid.Validate("spec.pxc"); err != nil {
+		t.Error(err)
+	}
+	for _, c := range []struct {
+		c    Component
+		path string
+	}{
+		{Component{Size: -1}, "spec.pxc.size"},
+		{Component{Requests: Resources{CPU: "two"}}, "spec.pxc.resources.requests.cpu"},
+		{Component{Limits: Resources{Memory: "1G,2G"}}, "spec.pxc.resources.limits.memory"},
+		{Component{Storage: "6 GB"}, "spec.pxc.volumespec.persistentvolumeclaim.resources.requests"},
+	} {
+		var invalid ErrInvalidOption
+		if err := c.c.Validate("spec.pxc"); !errors.As(err, &invalid) || invalid.Path != c.path {
+			t.Errorf("%+v: expected the invalid option %s, got %v", c.c, c.path, err)
+		}
+	}
+}